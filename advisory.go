@@ -0,0 +1,68 @@
+package sq
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// AdvisoryKey returns a stable advisory lock key for name, which is the 64-bit
+// FNV-1a hash of name.
+//
+//     pool.WithAdvisoryLock(ctx, AdvisoryKey("jobs.cleanup"), cleanup)
+func AdvisoryKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+func (p *pgxPool) WithAdvisoryLock(ctx context.Context, key int64, fn func() error) error {
+	_, err := p.advisoryLock(ctx, false, key, fn)
+	return err
+}
+
+func (p *pgxPool) TryAdvisoryLock(ctx context.Context, key int64, fn func() error) (bool, error) {
+	return p.advisoryLock(ctx, true, key, fn)
+}
+
+// advisoryLock calls fn holding a session-level advisory lock, on a
+// connection that is pinned until the lock is released.
+func (p *pgxPool) advisoryLock(ctx context.Context, try bool, key int64, fn func() error) (locked bool, err error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Release()
+
+	if try {
+		err = queryRow(ctx, conn, Expr("SELECT pg_try_advisory_lock(?)", key)).Scan(&locked)
+	} else {
+		_, err = exec(ctx, conn, Expr("SELECT pg_advisory_lock(?)", key))
+		locked = err == nil
+	}
+	if err != nil || !locked {
+		return false, err
+	}
+
+	defer func() {
+		// The lock must not be returned to the pool with the connection, so it
+		// is released regardless of ctx and the connection is closed if that
+		// fails.
+		if _, unlockErr := exec(context.Background(), conn, Expr("SELECT pg_advisory_unlock(?)", key)); unlockErr != nil {
+			_ = conn.Conn().Close(context.Background())
+			if err == nil {
+				err = unlockErr
+			}
+		}
+	}()
+
+	return true, fn()
+}
+
+func (tx *pgxTx) AdvisoryXactLock(ctx context.Context, key int64) error {
+	return advisoryXactLock(ctx, tx, key)
+}
+
+func advisoryXactLock(ctx context.Context, tx Executor, key int64) error {
+	_, err := tx.Exec(ctx, Expr("SELECT pg_advisory_xact_lock(?)", key))
+	return err
+}
@@ -0,0 +1,15 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisoryKey(t *testing.T) {
+	// The FNV-1a offset basis and the hash of "a" as signed integers.
+	assert.Equal(t, int64(-0x340d631b7bdddcdb), AdvisoryKey(""))
+	assert.Equal(t, int64(-0x509c23b379fe1374), AdvisoryKey("a"))
+	assert.Equal(t, AdvisoryKey("jobs.cleanup"), AdvisoryKey("jobs.cleanup"))
+	assert.NotEqual(t, AdvisoryKey("jobs.cleanup"), AdvisoryKey("jobs.cleanuq"))
+}
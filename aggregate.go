@@ -0,0 +1,152 @@
+package sq
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// AggregateBuilder builds SQL aggregate function calls.
+//
+// Like SelectBuilder, AggregateBuilder is immutable.
+type AggregateBuilder interface {
+	// Distinct adds DISTINCT to the aggregate arguments.
+	Distinct() AggregateBuilder
+
+	// OrderBy adds ORDER BY expressions to the aggregate arguments. Each
+	// expression may be a string or a StatementBuilder such as Desc.
+	//
+	//   Aggregate("string_agg", "name", "','").OrderBy("name")
+	OrderBy(orderBys ...interface{}) AggregateBuilder
+
+	// WithinGroup adds WITHIN GROUP ORDER BY expressions for ordered-set
+	// aggregates.
+	//
+	//   Aggregate("percentile_cont", Expr("?", 0.9)).WithinGroup("latency")
+	WithinGroup(orderBys ...interface{}) AggregateBuilder
+
+	// Filter adds an expression to the FILTER clause of the aggregate.
+	//
+	// Expressions are ANDed together, see SelectBuilder.Where.
+	//
+	//   Aggregate("count", "*").Filter(Eq{"status": "done"})
+	Filter(pred interface{}, args ...interface{}) AggregateBuilder
+
+	ToSQL() (sqlStr string, args []interface{}, err error)
+}
+
+type aggregateBuilder struct {
+	fn          string
+	exprs       []StatementBuilder
+	distinct    bool
+	orderBys    []StatementBuilder
+	withinGroup []StatementBuilder
+	filterParts []StatementBuilder
+}
+
+// Aggregate returns a new AggregateBuilder calling the aggregate function fn
+// with the given argument expressions. Each expression may be a string or a
+// StatementBuilder.
+//
+//     .Column(Alias(Aggregate("count", "*").Filter("status = ?", "done"), "done"))
+func Aggregate(fn string, exprs ...interface{}) AggregateBuilder {
+	b := &aggregateBuilder{fn: fn}
+	for _, e := range exprs {
+		b.exprs = append(b.exprs, newPart(e))
+	}
+	return b
+}
+
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *aggregateBuilder) clone() *aggregateBuilder {
+	nb := *b
+	nb.orderBys = nb.orderBys[:len(nb.orderBys):len(nb.orderBys)]
+	nb.withinGroup = nb.withinGroup[:len(nb.withinGroup):len(nb.withinGroup)]
+	nb.filterParts = nb.filterParts[:len(nb.filterParts):len(nb.filterParts)]
+	return &nb
+}
+
+func (b *aggregateBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
+	if len(b.fn) == 0 {
+		err = fmt.Errorf("aggregate expressions must specify a function")
+		return
+	}
+	if len(b.withinGroup) > 0 && (b.distinct || len(b.orderBys) > 0) {
+		err = fmt.Errorf("aggregate expressions with WITHIN GROUP cannot use DISTINCT or ORDER BY")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString(b.fn)
+	sql.WriteString("(")
+
+	if b.distinct {
+		sql.WriteString("DISTINCT ")
+	}
+
+	if len(b.exprs) > 0 {
+		args, err = appendToSQL(b.exprs, sql, ", ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.orderBys) > 0 {
+		sql.WriteString(" ORDER BY ")
+		args, err = appendToSQL(b.orderBys, sql, ", ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	sql.WriteString(")")
+
+	if len(b.withinGroup) > 0 {
+		sql.WriteString(" WITHIN GROUP (ORDER BY ")
+		args, err = appendToSQL(b.withinGroup, sql, ", ", args)
+		if err != nil {
+			return
+		}
+		sql.WriteString(")")
+	}
+
+	if len(b.filterParts) > 0 {
+		sql.WriteString(" FILTER (WHERE ")
+		args, err = appendToSQL(b.filterParts, sql, " AND ", args)
+		if err != nil {
+			return
+		}
+		sql.WriteString(")")
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+func (b *aggregateBuilder) Distinct() AggregateBuilder {
+	b = b.clone()
+	b.distinct = true
+	return b
+}
+
+func (b *aggregateBuilder) OrderBy(orderBys ...interface{}) AggregateBuilder {
+	b = b.clone()
+	for _, o := range orderBys {
+		b.orderBys = append(b.orderBys, newPart(o))
+	}
+	return b
+}
+
+func (b *aggregateBuilder) WithinGroup(orderBys ...interface{}) AggregateBuilder {
+	b = b.clone()
+	for _, o := range orderBys {
+		b.withinGroup = append(b.withinGroup, newPart(o))
+	}
+	return b
+}
+
+func (b *aggregateBuilder) Filter(pred interface{}, args ...interface{}) AggregateBuilder {
+	b = b.clone()
+	b.filterParts = append(b.filterParts, newWherePart(pred, args...))
+	return b
+}
@@ -0,0 +1,51 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateToSQL(t *testing.T) {
+	qb := Select("team").
+		Column(Alias(Aggregate("count", "*").Filter(Eq{"status": "done"}), "done")).
+		Column(Alias(Aggregate("count", "*").Filter("status = ?", "open").Filter("age > ?", 7), "stale")).
+		Column(Alias(Aggregate("string_agg", "name", Expr("?", ",")).Distinct().OrderBy("name", Desc("id")), "names")).
+		Column(Alias(Aggregate("percentile_cont", Expr("?", 0.9)).WithinGroup("latency"), "p90")).
+		From("tasks").
+		Where("org = ?", 1).
+		GroupBy("team").
+		Having(Expr("? > ?", Aggregate("count", "*").Filter("status = ?", "open"), 10))
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT team, " +
+		"(count(*) FILTER (WHERE status = ?)) AS done, " +
+		"(count(*) FILTER (WHERE status = ? AND age > ?)) AS stale, " +
+		"(string_agg(DISTINCT name, ? ORDER BY name, id DESC)) AS names, " +
+		"(percentile_cont(?) WITHIN GROUP (ORDER BY latency)) AS p90 " +
+		"FROM tasks WHERE org = ? GROUP BY team " +
+		"HAVING count(*) FILTER (WHERE status = ?) > ?"
+	assert.Equal(t, expectedSQL, sql)
+
+	expectedArgs := []interface{}{"done", "open", 7, ",", 0.9, 1, "open", 10}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestAggregateImmutable(t *testing.T) {
+	base := Aggregate("count", "*").Filter("a = ?", 1)
+	_ = base.Filter("b = ?", 2)
+
+	sql, args, err := base.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "count(*) FILTER (WHERE a = ?)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestAggregateToSQLErr(t *testing.T) {
+	_, _, err := Aggregate("").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Aggregate("mode").OrderBy("a").WithinGroup("b").ToSQL()
+	assert.Error(t, err)
+}
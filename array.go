@@ -0,0 +1,141 @@
+package sq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// arrayExpr is an ANY or ALL array comparison operand.
+type arrayExpr struct {
+	fn    string
+	value interface{}
+}
+
+// Any builds an ANY(?) array comparison operand, binding value as a single
+// PostgreSQL array parameter. Value may also be a StatementBuilder such as a
+// subquery.
+//
+//     .Where(Eq{"id": Any([]int64{1, 2, 3})}) == "id = ANY(?)"
+func Any(value interface{}) StatementBuilder {
+	return arrayExpr{fn: "ANY", value: value}
+}
+
+// All builds an ALL(?) array comparison operand.
+//
+//     .Where(Gt{"score": All([]int{1, 2, 3})}) == "score > ALL(?)"
+//
+// See Any.
+func All(value interface{}) StatementBuilder {
+	return arrayExpr{fn: "ALL", value: value}
+}
+
+func (e arrayExpr) ToSQL() (sql string, args []interface{}, err error) {
+	switch v := e.value.(type) {
+	case nil:
+		err = fmt.Errorf("%s operand must not be null", e.fn)
+	case StatementBuilder:
+		sql, args, err = v.ToSQL()
+		if err == nil {
+			sql = fmt.Sprintf("%s(%s)", e.fn, sql)
+		}
+	default:
+		sql = e.fn + "(?)"
+		args = []interface{}{v}
+	}
+	return
+}
+
+// EqAny is syntactic sugar for use with Where/Having/Set methods. Unlike Eq,
+// slices are bound as a single array parameter, so the generated SQL does not
+// depend on the number of values.
+//
+//     .Where(EqAny{"id": []int64{1, 2, 3}}) == "id = ANY(?)"
+type EqAny map[string]interface{}
+
+func (eq EqAny) ToSQL() (sql string, args []interface{}, err error) {
+	m := make(Eq, len(eq))
+	for key, val := range eq {
+		m[key] = Any(val)
+	}
+	return m.ToSQL()
+}
+
+// NotEqAll is syntactic sugar for use with Where/Having/Set methods. It is the
+// array parameter equivalent of NotEq with a slice value.
+//
+//     .Where(NotEqAll{"id": []int64{1, 2, 3}}) == "id <> ALL(?)"
+type NotEqAll map[string]interface{}
+
+func (neq NotEqAll) ToSQL() (sql string, args []interface{}, err error) {
+	m := make(NotEq, len(neq))
+	for key, val := range neq {
+		m[key] = All(val)
+	}
+	return m.ToSQL()
+}
+
+// ArrayContains is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(ArrayContains{"tags": []string{"a", "b"}}) == "tags @> ?"
+type ArrayContains map[string]interface{}
+
+func (c ArrayContains) ToSQL() (sql string, args []interface{}, err error) {
+	return binaryOpToSQL(c, "@>")
+}
+
+// ArrayContainedBy is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(ArrayContainedBy{"tags": []string{"a", "b"}}) == "tags <@ ?"
+type ArrayContainedBy map[string]interface{}
+
+func (c ArrayContainedBy) ToSQL() (sql string, args []interface{}, err error) {
+	return binaryOpToSQL(c, "<@")
+}
+
+// ArrayOverlap is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(ArrayOverlap{"tags": []string{"a", "b"}}) == "tags && ?"
+type ArrayOverlap map[string]interface{}
+
+func (o ArrayOverlap) ToSQL() (sql string, args []interface{}, err error) {
+	return binaryOpToSQL(o, "&&")
+}
+
+// binaryOpToSQL renders "<key> <opr> ?" for each key in m, binding the value
+// as is. StatementBuilder values are inlined.
+func binaryOpToSQL(m map[string]interface{}, opr string) (sql string, args []interface{}, err error) {
+	var exprs []string
+
+	for key, val := range m {
+		expr := ""
+
+		switch v := val.(type) {
+		case driver.Valuer:
+			if val, err = v.Value(); err != nil {
+				return
+			}
+		}
+
+		if val == nil {
+			err = fmt.Errorf("cannot use null with %s operator", opr)
+			return
+		} else if v, ok := val.(StatementBuilder); ok {
+			var s string
+			var a []interface{}
+			s, a, err = v.ToSQL()
+			if err != nil {
+				return
+			}
+
+			expr = fmt.Sprintf("%s %s %s", key, opr, s)
+			args = append(args, a...)
+		} else {
+			expr = fmt.Sprintf("%s %s ?", key, opr)
+			args = append(args, val)
+		}
+		exprs = append(exprs, expr)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
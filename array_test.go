@@ -0,0 +1,51 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqAnyToSQL(t *testing.T) {
+	ids := []int64{1, 2, 3}
+
+	sql, args, err := EqAny{"id": ids}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ANY(?)", sql)
+	assert.Equal(t, []interface{}{ids}, args)
+
+	sql, args, err = Eq{"id": Any(ids)}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ANY(?)", sql)
+	assert.Equal(t, []interface{}{ids}, args)
+
+	sql, args, err = NotEqAll{"id": ids}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "id <> ALL(?)", sql)
+	assert.Equal(t, []interface{}{ids}, args)
+
+	sql, args, err = Gt{"score": All(Select("score").From("s").Where("x = ?", 1))}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "score > ALL(SELECT score FROM s WHERE x = ?)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = EqAny{"id": nil}.ToSQL()
+	assert.Error(t, err)
+}
+
+func TestArrayOperatorsToSQL(t *testing.T) {
+	tags := []string{"a", "b"}
+
+	qb := Select("id").
+		From("t").
+		Where(ArrayContains{"tags": tags}).
+		Where(ArrayContainedBy{"tags": Expr("ARRAY[?, ?]", "c", "d")}).
+		Where(ArrayOverlap{"tags": tags})
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE tags @> ? AND tags <@ ARRAY[?, ?] AND tags && ?", sql)
+	assert.Equal(t, []interface{}{tags, "c", "d", tags}, args)
+
+	_, _, err = ArrayOverlap{"tags": nil}.ToSQL()
+	assert.Error(t, err)
+}
@@ -6,6 +6,8 @@ import (
 )
 
 // CaseBuilder builds SQL CASE construct which could be used as parts of queries.
+//
+// Like SelectBuilder, CaseBuilder is immutable.
 type CaseBuilder interface {
 	// When adds "WHEN ... THEN ..." part to CASE construct
 	When(when interface{}, then interface{}) CaseBuilder
@@ -22,6 +24,13 @@ type caseBuilder struct {
 	elsePart  StatementBuilder
 }
 
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *caseBuilder) clone() *caseBuilder {
+	nb := *b
+	nb.whenParts = nb.whenParts[:len(nb.whenParts):len(nb.whenParts)]
+	return &nb
+}
+
 func (b *caseBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	if len(b.whenParts) == 0 {
 		err = errors.New("case expression must contain at lease one WHEN clause")
@@ -55,11 +64,13 @@ func (b *caseBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 // what sets optional value for CASE construct "CASE [value] ..."
 func (b *caseBuilder) what(expr interface{}) CaseBuilder {
+	b = b.clone()
 	b.whatPart = newPart(expr)
 	return b
 }
 
 func (b *caseBuilder) When(when interface{}, then interface{}) CaseBuilder {
+	b = b.clone()
 	// TODO: performance hint: replace slice of WhenPart with just slice of parts
 	// where even indices of the slice belong to "when"s and odd indices belong to "then"s
 	b.whenParts = append(b.whenParts, newWhenPart(when, then))
@@ -67,6 +78,7 @@ func (b *caseBuilder) When(when interface{}, then interface{}) CaseBuilder {
 }
 
 func (b *caseBuilder) Else(expr interface{}) CaseBuilder {
+	b = b.clone()
 	b.elsePart = newPart(expr)
 	return b
 
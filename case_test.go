@@ -1,6 +1,7 @@
 package sq
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -139,3 +140,21 @@ func TestCaseWithNoWhenClause(t *testing.T) {
 
 	assert.Equal(t, "case expression must contain at lease one WHEN clause", err.Error())
 }
+
+func TestCaseBuilderConcurrent(t *testing.T) {
+	base := Case("a").When("1", "'one'")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.When("2", Expr("?", i)).Else("'other'").ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, "CASE a WHEN 1 THEN 'one' WHEN 2 THEN ? ELSE 'other' END", sql)
+			assert.Equal(t, []interface{}{i}, args)
+		}(i)
+	}
+	wg.Wait()
+}
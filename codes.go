@@ -340,3 +340,265 @@ const (
 	CodeDataCorrupted                        = "XX001"
 	CodeIndexCorrupted                       = "XX002"
 )
+
+// conditionNames maps error codes to the condition names of the PostgreSQL
+// documentation, see ConditionName.
+var conditionNames = map[string]string{
+	CodeSuccessfulCompletion:                            "successful_completion",
+	CodeWarning:                                         "warning",
+	CodeDynamicResultSetsReturned:                       "dynamic_result_sets_returned",
+	CodeImplicitZeroBitPadding:                          "implicit_zero_bit_padding",
+	CodeNullValueEliminatedInSetFunction:                "null_value_eliminated_in_set_function",
+	CodePrivilegeNotGranted:                             "privilege_not_granted",
+	CodePrivilegeNotRevoked:                             "privilege_not_revoked",
+	CodeStringDataRightTruncationWarning:                "string_data_right_truncation",
+	CodeDeprecatedFeature:                               "deprecated_feature",
+	CodeNoData:                                          "no_data",
+	CodeNoAdditionalDynamicResultSetsReturned:           "no_additional_dynamic_result_sets_returned",
+	CodeSQLStatementNotYetComplete:                      "sql_statement_not_yet_complete",
+	CodeConnectionException:                             "connection_exception",
+	CodeConnectionDoesNotExist:                          "connection_does_not_exist",
+	CodeConnectionFailure:                               "connection_failure",
+	CodeSQLClientUnableToEstablishSqlconnection:         "sqlclient_unable_to_establish_sqlconnection",
+	CodeSqlserverRejectedEstablishmentOfSqlconnection:   "sqlserver_rejected_establishment_of_sqlconnection",
+	CodeTransactionResolutionUnknown:                    "transaction_resolution_unknown",
+	CodeProtocolViolation:                               "protocol_violation",
+	CodeTriggeredActionException:                        "triggered_action_exception",
+	CodeFeatureNotSupported:                             "feature_not_supported",
+	CodeInvalidTransactionInitiation:                    "invalid_transaction_initiation",
+	CodeLocatorException:                                "locator_exception",
+	CodeInvalidLocatorSpecification:                     "invalid_locator_specification",
+	CodeInvalidGrantor:                                  "invalid_grantor",
+	CodeInvalidGrantOperation:                           "invalid_grant_operation",
+	CodeInvalidRoleSpecification:                        "invalid_role_specification",
+	CodeDiagnosticsException:                            "diagnostics_exception",
+	CodeStackedDiagnosticsAccessedWithoutActiveHandler:  "stacked_diagnostics_accessed_without_active_handler",
+	CodeCaseNotFound:                                    "case_not_found",
+	CodeCardinalityViolation:                            "cardinality_violation",
+	CodeDataException:                                   "data_exception",
+	CodeArraySubscriptError:                             "array_subscript_error",
+	CodeCharacterNotInRepertoire:                        "character_not_in_repertoire",
+	CodeDatetimeFieldOverflow:                           "datetime_field_overflow",
+	CodeDivisionByZero:                                  "division_by_zero",
+	CodeErrorInAssignment:                               "error_in_assignment",
+	CodeEscapeCharacterConflict:                         "escape_character_conflict",
+	CodeIndicatorOverflow:                               "indicator_overflow",
+	CodeIntervalFieldOverflow:                           "interval_field_overflow",
+	CodeInvalidArgumentForLogarithm:                     "invalid_argument_for_logarithm",
+	CodeInvalidArgumentForNtileFunction:                 "invalid_argument_for_ntile_function",
+	CodeInvalidArgumentForNthValueFunction:              "invalid_argument_for_nth_value_function",
+	CodeInvalidArgumentForPowerFunction:                 "invalid_argument_for_power_function",
+	CodeInvalidArgumentForWidthBucketFunction:           "invalid_argument_for_width_bucket_function",
+	CodeInvalidCharacterValueForCast:                    "invalid_character_value_for_cast",
+	CodeInvalidDatetimeFormat:                           "invalid_datetime_format",
+	CodeInvalidEscapeCharacter:                          "invalid_escape_character",
+	CodeInvalidEscapeOctet:                              "invalid_escape_octet",
+	CodeInvalidEscapeSequence:                           "invalid_escape_sequence",
+	CodeNonstandardUseOfEscapeCharacter:                 "nonstandard_use_of_escape_character",
+	CodeInvalidIndicatorParameterValue:                  "invalid_indicator_parameter_value",
+	CodeInvalidParameterValue:                           "invalid_parameter_value",
+	CodeInvalidPrecedingOrFollowingSize:                 "invalid_preceding_or_following_size",
+	CodeInvalidRegularExpression:                        "invalid_regular_expression",
+	CodeInvalidRowCountInLimitClause:                    "invalid_row_count_in_limit_clause",
+	CodeInvalidRowCountInResultOffsetClause:             "invalid_row_count_in_result_offset_clause",
+	CodeInvalidTablesampleArgument:                      "invalid_tablesample_argument",
+	CodeInvalidTablesampleRepeat:                        "invalid_tablesample_repeat",
+	CodeInvalidTimeZoneDisplacementValue:                "invalid_time_zone_displacement_value",
+	CodeInvalidUseOfEscapeCharacter:                     "invalid_use_of_escape_character",
+	CodeMostSpecificTypeMismatch:                        "most_specific_type_mismatch",
+	CodeNullValueNotAllowed:                             "null_value_not_allowed",
+	CodeNullValueNoIndicatorParameter:                   "null_value_no_indicator_parameter",
+	CodeNumericValueOutOfRange:                          "numeric_value_out_of_range",
+	CodeSequenceGeneratorLimitExceeded:                  "sequence_generator_limit_exceeded",
+	CodeStringDataLengthMismatch:                        "string_data_length_mismatch",
+	CodeStringDataRightTruncation:                       "string_data_right_truncation",
+	CodeSubstringError:                                  "substring_error",
+	CodeTrimError:                                       "trim_error",
+	CodeUnterminatedCString:                             "unterminated_c_string",
+	CodeZeroLengthCharacterString:                       "zero_length_character_string",
+	CodeFloatingPointException:                          "floating_point_exception",
+	CodeInvalidTextRepresentation:                       "invalid_text_representation",
+	CodeInvalidBinaryRepresentation:                     "invalid_binary_representation",
+	CodeBadCopyFileFormat:                               "bad_copy_file_format",
+	CodeUntranslatableCharacter:                         "untranslatable_character",
+	CodeNotAnXMLDocument:                                "not_an_xml_document",
+	CodeInvalidXMLDocument:                              "invalid_xml_document",
+	CodeInvalidXMLContent:                               "invalid_xml_content",
+	CodeInvalidXMLComment:                               "invalid_xml_comment",
+	CodeInvalidXMLProcessingInstruction:                 "invalid_xml_processing_instruction",
+	CodeDuplicateJSONObjectKeyValue:                     "duplicate_json_object_key_value",
+	CodeInvalidJSONText:                                 "invalid_json_text",
+	CodeInvalidSQLJSONSubscript:                         "invalid_sql_json_subscript",
+	CodeMoreThanOneSQLJSONItem:                          "more_than_one_sql_json_item",
+	CodeNoSQLJSONItem:                                   "no_sql_json_item",
+	CodeNonNumericSQLJSONItem:                           "non_numeric_sql_json_item",
+	CodeNonUniqueKeysInAJSONObject:                      "non_unique_keys_in_a_json_object",
+	CodeSingletonSQLJSONItemRequired:                    "singleton_sql_json_item_required",
+	CodeSQLJSONArrayNotFound:                            "sql_json_array_not_found",
+	CodeSQLJSONMemberNotFound:                           "sql_json_member_not_found",
+	CodeSQLJSONNumberNotFound:                           "sql_json_number_not_found",
+	CodeSQLJSONObjectNotFound:                           "sql_json_object_not_found",
+	CodeTooManyJSONArrayElements:                        "too_many_json_array_elements",
+	CodeTooManyJSONObjectMembers:                        "too_many_json_object_members",
+	CodeSQLJSONScalarRequired:                           "sql_json_scalar_required",
+	CodeIntegrityConstraintViolation:                    "integrity_constraint_violation",
+	CodeRestrictViolation:                               "restrict_violation",
+	CodeNotNullViolation:                                "not_null_violation",
+	CodeForeignKeyViolation:                             "foreign_key_violation",
+	CodeUniqueViolation:                                 "unique_violation",
+	CodeCheckViolation:                                  "check_violation",
+	CodeExclusionViolation:                              "exclusion_violation",
+	CodeInvalidCursorState:                              "invalid_cursor_state",
+	CodeInvalidTransactionState:                         "invalid_transaction_state",
+	CodeActiveSQLTransaction:                            "active_sql_transaction",
+	CodeBranchTransactionAlreadyActive:                  "branch_transaction_already_active",
+	CodeHeldCursorRequiresSameIsolationLevel:            "held_cursor_requires_same_isolation_level",
+	CodeInappropriateAccessModeForBranchTransaction:     "inappropriate_access_mode_for_branch_transaction",
+	CodeInappropriateIsolationLevelForBranchTransaction: "inappropriate_isolation_level_for_branch_transaction",
+	CodeNoActiveSQLTransactionForBranchTransaction:      "no_active_sql_transaction_for_branch_transaction",
+	CodeReadOnlySQLTransaction:                          "read_only_sql_transaction",
+	CodeSchemaAndDataStatementMixingNotSupported:        "schema_and_data_statement_mixing_not_supported",
+	CodeNoActiveSQLTransaction:                          "no_active_sql_transaction",
+	CodeInFailedSQLTransaction:                          "in_failed_sql_transaction",
+	CodeIdleInTransactionSessionTimeout:                 "idle_in_transaction_session_timeout",
+	CodeInvalidSQLStatementName:                         "invalid_sql_statement_name",
+	CodeTriggeredDataChangeViolation:                    "triggered_data_change_violation",
+	CodeInvalidAuthorizationSpecification:               "invalid_authorization_specification",
+	CodeInvalidPassword:                                 "invalid_password",
+	CodeDependentPrivilegeDescriptorsStillExist:         "dependent_privilege_descriptors_still_exist",
+	CodeDependentObjectsStillExist:                      "dependent_objects_still_exist",
+	CodeInvalidTransactionTermination:                   "invalid_transaction_termination",
+	CodeSQLRoutineException:                             "sql_routine_exception",
+	CodeFunctionExecutedNoReturnStatement:               "function_executed_no_return_statement",
+	CodeModifyingSQLDataNotPermitted:                    "modifying_sql_data_not_permitted",
+	CodeProhibitedSQLStatementAttempted:                 "prohibited_sql_statement_attempted",
+	CodeReadingSQLDataNotPermitted:                      "reading_sql_data_not_permitted",
+	CodeInvalidCursorName:                               "invalid_cursor_name",
+	CodeExternalRoutineException:                        "external_routine_exception",
+	CodeExternalContainingSQLNotPermitted:               "containing_sql_not_permitted",
+	CodeExternalModifyingSQLDataNotPermitted:            "modifying_sql_data_not_permitted",
+	CodeExternalProhibitedSQLStatementAttempted:         "prohibited_sql_statement_attempted",
+	CodeExternalReadingSQLDataNotPermitted:              "reading_sql_data_not_permitted",
+	CodeExternalRoutineInvocationException:              "external_routine_invocation_exception",
+	CodeExternalInvalidSqlstateReturned:                 "invalid_sqlstate_returned",
+	CodeExternalNullValueNotAllowed:                     "null_value_not_allowed",
+	CodeExternalTriggerProtocolViolated:                 "trigger_protocol_violated",
+	CodeExternalSRFProtocolViolated:                     "srf_protocol_violated",
+	CodeExternalEventTriggerProtocolViolated:            "event_trigger_protocol_violated",
+	CodeSavepointException:                              "savepoint_exception",
+	CodeInvalidSavepointSpecification:                   "invalid_savepoint_specification",
+	CodeInvalidCatalogName:                              "invalid_catalog_name",
+	CodeInvalidSchemaName:                               "invalid_schema_name",
+	CodeTransactionRollback:                             "transaction_rollback",
+	CodeTransactionIntegrityConstraintViolation:         "transaction_integrity_constraint_violation",
+	CodeSerializationFailure:                            "serialization_failure",
+	CodeStatementCompletionUnknown:                      "statement_completion_unknown",
+	CodeDeadlockDetected:                                "deadlock_detected",
+	CodeSyntaxErrorOrAccessRuleViolation:                "syntax_error_or_access_rule_violation",
+	CodeSyntaxError:                                     "syntax_error",
+	CodeInsufficientPrivilege:                           "insufficient_privilege",
+	CodeCannotCoerce:                                    "cannot_coerce",
+	CodeGroupingError:                                   "grouping_error",
+	CodeWindowingError:                                  "windowing_error",
+	CodeInvalidRecursion:                                "invalid_recursion",
+	CodeInvalidForeignKey:                               "invalid_foreign_key",
+	CodeInvalidName:                                     "invalid_name",
+	CodeNameTooLong:                                     "name_too_long",
+	CodeReservedName:                                    "reserved_name",
+	CodeDatatypeMismatch:                                "datatype_mismatch",
+	CodeIndeterminateDatatype:                           "indeterminate_datatype",
+	CodeCollationMismatch:                               "collation_mismatch",
+	CodeIndeterminateCollation:                          "indeterminate_collation",
+	CodeWrongObjectType:                                 "wrong_object_type",
+	CodeGeneratedAlways:                                 "generated_always",
+	CodeUndefinedColumn:                                 "undefined_column",
+	CodeUndefinedFunction:                               "undefined_function",
+	CodeUndefinedTable:                                  "undefined_table",
+	CodeUndefinedParameter:                              "undefined_parameter",
+	CodeUndefinedObject:                                 "undefined_object",
+	CodeDuplicateColumn:                                 "duplicate_column",
+	CodeDuplicateCursor:                                 "duplicate_cursor",
+	CodeDuplicateDatabase:                               "duplicate_database",
+	CodeDuplicateFunction:                               "duplicate_function",
+	CodeDuplicatePreparedStatement:                      "duplicate_prepared_statement",
+	CodeDuplicateSchema:                                 "duplicate_schema",
+	CodeDuplicateTable:                                  "duplicate_table",
+	CodeDuplicateAlias:                                  "duplicate_alias",
+	CodeDuplicateObject:                                 "duplicate_object",
+	CodeAmbiguousColumn:                                 "ambiguous_column",
+	CodeAmbiguousFunction:                               "ambiguous_function",
+	CodeAmbiguousParameter:                              "ambiguous_parameter",
+	CodeAmbiguousAlias:                                  "ambiguous_alias",
+	CodeInvalidColumnReference:                          "invalid_column_reference",
+	CodeInvalidColumnDefinition:                         "invalid_column_definition",
+	CodeInvalidCursorDefinition:                         "invalid_cursor_definition",
+	CodeInvalidDatabaseDefinition:                       "invalid_database_definition",
+	CodeInvalidFunctionDefinition:                       "invalid_function_definition",
+	CodeInvalidPreparedStatementDefinition:              "invalid_prepared_statement_definition",
+	CodeInvalidSchemaDefinition:                         "invalid_schema_definition",
+	CodeInvalidTableDefinition:                          "invalid_table_definition",
+	CodeInvalidObjectDefinition:                         "invalid_object_definition",
+	CodeWithCheckOptionViolation:                        "with_check_option_violation",
+	CodeInsufficientResources:                           "insufficient_resources",
+	CodeDiskFull:                                        "disk_full",
+	CodeOutOfMemory:                                     "out_of_memory",
+	CodeTooManyConnections:                              "too_many_connections",
+	CodeConfigurationLimitExceeded:                      "configuration_limit_exceeded",
+	CodeProgramLimitExceeded:                            "program_limit_exceeded",
+	CodeStatementTooComplex:                             "statement_too_complex",
+	CodeTooManyColumns:                                  "too_many_columns",
+	CodeTooManyArguments:                                "too_many_arguments",
+	CodeObjectNotInPrerequisiteState:                    "object_not_in_prerequisite_state",
+	CodeObjectInUse:                                     "object_in_use",
+	CodeCantChangeRuntimeParam:                          "cant_change_runtime_param",
+	CodeLockNotAvailable:                                "lock_not_available",
+	CodeUnsafeNewEnumValueUsage:                         "unsafe_new_enum_value_usage",
+	CodeOperatorIntervention:                            "operator_intervention",
+	CodeQueryCanceled:                                   "query_canceled",
+	CodeAdminShutdown:                                   "admin_shutdown",
+	CodeCrashShutdown:                                   "crash_shutdown",
+	CodeCannotConnectNow:                                "cannot_connect_now",
+	CodeDatabaseDropped:                                 "database_dropped",
+	CodeSystemError:                                     "system_error",
+	CodeIOError:                                         "io_error",
+	CodeUndefinedFile:                                   "undefined_file",
+	CodeDuplicateFile:                                   "duplicate_file",
+	CodeSnapshotTooOld:                                  "snapshot_too_old",
+	CodeConfigFileError:                                 "config_file_error",
+	CodeLockFileExists:                                  "lock_file_exists",
+	CodeFDWError:                                        "fdw_error",
+	CodeFDWColumnNameNotFound:                           "fdw_column_name_not_found",
+	CodeFDWDynamicParameterValueNeeded:                  "fdw_dynamic_parameter_value_needed",
+	CodeFDWFunctionSequenceError:                        "fdw_function_sequence_error",
+	CodeFDWInconsistentDescriptorInformation:            "fdw_inconsistent_descriptor_information",
+	CodeFDWInvalidAttributeValue:                        "fdw_invalid_attribute_value",
+	CodeFDWInvalidColumnName:                            "fdw_invalid_column_name",
+	CodeFDWInvalidColumnNumber:                          "fdw_invalid_column_number",
+	CodeFDWInvalidDataType:                              "fdw_invalid_data_type",
+	CodeFDWInvalidDataTypeDescriptors:                   "fdw_invalid_data_type_descriptors",
+	CodeFDWInvalidDescriptorFieldIdentifier:             "fdw_invalid_descriptor_field_identifier",
+	CodeFDWInvalidHandle:                                "fdw_invalid_handle",
+	CodeFDWInvalidOptionIndex:                           "fdw_invalid_option_index",
+	CodeFDWInvalidOptionName:                            "fdw_invalid_option_name",
+	CodeFDWInvalidStringLengthOrBufferLength:            "fdw_invalid_string_length_or_buffer_length",
+	CodeFDWInvalidStringFormat:                          "fdw_invalid_string_format",
+	CodeFDWInvalidUseOfNullPointer:                      "fdw_invalid_use_of_null_pointer",
+	CodeFDWTooManyHandles:                               "fdw_too_many_handles",
+	CodeFDWOutOfMemory:                                  "fdw_out_of_memory",
+	CodeFDWNoSchemas:                                    "fdw_no_schemas",
+	CodeFDWOptionNameNotFound:                           "fdw_option_name_not_found",
+	CodeFDWReplyHandle:                                  "fdw_reply_handle",
+	CodeFDWSchemaNotFound:                               "fdw_schema_not_found",
+	CodeFDWTableNotFound:                                "fdw_table_not_found",
+	CodeFDWUnableToCreateExecution:                      "fdw_unable_to_create_execution",
+	CodeFDWUnableToCreateReply:                          "fdw_unable_to_create_reply",
+	CodeFDWUnableToEstablishConnection:                  "fdw_unable_to_establish_connection",
+	CodePLPGSQLError:                                    "plpgsql_error",
+	CodeRaiseException:                                  "raise_exception",
+	CodeNoDataFound:                                     "no_data_found",
+	CodeTooManyRows:                                     "too_many_rows",
+	CodeAssertFailure:                                   "assert_failure",
+	CodeInternalError:                                   "internal_error",
+	CodeDataCorrupted:                                   "data_corrupted",
+	CodeIndexCorrupted:                                  "index_corrupted",
+}
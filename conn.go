@@ -0,0 +1,96 @@
+package sq
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Conn is a connection acquired from a Pool, see Pool.Acquire.
+type Conn interface {
+	Executor
+
+	// Tx calls fn in a transaction on the connection.
+	//
+	// See Pool.Tx.
+	Tx(ctx context.Context, fn func(tx Tx) error) error
+}
+
+func (p *pgxPool) Acquire(ctx context.Context, fn func(conn Conn) error) error {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	return fn(&pgxConn{conn: conn})
+}
+
+type pgxConn struct {
+	conn *pgxpool.Conn
+}
+
+func (c *pgxConn) Tx(ctx context.Context, fn func(tx Tx) error) error {
+	return pgxTxExecute(ctx, c.conn, fn)
+}
+
+func (c *pgxConn) Exec(ctx context.Context, qb StatementBuilder) (Result, error) {
+	return exec(ctx, c.conn, qb)
+}
+
+func (c *pgxConn) Query(ctx context.Context, qb StatementBuilder) (Rows, error) {
+	return query(ctx, c.conn, qb)
+}
+
+func (c *pgxConn) QueryRow(ctx context.Context, qb StatementBuilder) Row {
+	return queryRow(ctx, c.conn, qb)
+}
+
+func (c *pgxConn) All(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return all(ctx, c.conn, qb, dst)
+}
+
+func (c *pgxConn) One(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return one(ctx, c.conn, qb, dst)
+}
+
+func (c *pgxConn) OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error) {
+	return oneOrNone(ctx, c.conn, qb, dst)
+}
+
+func (c *pgxConn) Exists(ctx context.Context, qb StatementBuilder) (bool, error) {
+	return exists(ctx, c.conn, qb)
+}
+
+func (c *pgxConn) Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return scalar(ctx, c.conn, qb, dst)
+}
+
+func (c *pgxConn) Column(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return column(ctx, c.conn, qb, dst)
+}
+
+func (c *pgxConn) Map(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return mapRows(ctx, c.conn, qb, dst)
+}
+
+func (c *pgxConn) Notify(ctx context.Context, channel, payload string) error {
+	return notify(ctx, c, channel, payload)
+}
+
+// pgxTxExecute calls fn in a serializable transaction begun by b.
+func pgxTxExecute(ctx context.Context, b pgxBeginner, fn func(tx Tx) error) error {
+	pgxtx, err := b.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.Serializable,
+	})
+	if err != nil {
+		return err
+	}
+	tx := &pgxTx{tx: pgxtx}
+	return txExecute(ctx, tx, fn)
+}
+
+type pgxBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
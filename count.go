@@ -0,0 +1,30 @@
+package sq
+
+// CountOf returns a query counting the rows returned by qb, ignoring its
+// ORDER BY, LIMIT, OFFSET and FETCH FIRST clauses, such as for the total of a
+// paginated list.
+//
+// Qb is counted as a subquery, as its result columns may change the number of
+// rows, such as with aggregate or set-returning functions.
+//
+//     CountOf(Select("id").From("users").Where("active").OrderBy("id").Limit(10)) ==
+//       "SELECT count(*) FROM (SELECT id FROM users WHERE active) AS t"
+func CountOf(qb SelectBuilder) SelectBuilder {
+	b, ok := qb.(*selectBuilder)
+	if !ok {
+		return countSubquery(qb)
+	}
+
+	b = b.clone()
+	b.orderBys = nil
+	b.limit = nil
+	b.offset = nil
+	b.fetch = nil
+	b.fetchWithTies = false
+
+	return countSubquery(b)
+}
+
+func countSubquery(qb StatementBuilder) SelectBuilder {
+	return Select("count(*)").FromClause("(?) AS t", qb)
+}
@@ -0,0 +1,45 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountOf(t *testing.T) {
+	qb := Select("id", "name").
+		From("users").
+		Where("active = ?", true).
+		OrderBy("name").
+		Limit(10).
+		Offset(20).
+		BindLimitOffset()
+
+	sql, args, err := CountOf(qb).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM (SELECT id, name FROM users WHERE active = ?) AS t", sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	sql, _, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users WHERE active = ? ORDER BY name LIMIT ? OFFSET ?", sql)
+}
+
+func TestCountOfSubquery(t *testing.T) {
+	sql, args, err := CountOf(Select("user_id").From("orders").Where("total > ?", 10).GroupBy("user_id").OrderBy("user_id")).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM (SELECT user_id FROM orders WHERE total > ? GROUP BY user_id) AS t", sql)
+	assert.Equal(t, []interface{}{10}, args)
+
+	sql, _, err = CountOf(Select("max(total)").From("orders")).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM (SELECT max(total) FROM orders) AS t", sql)
+
+	sql, _, err = CountOf(Select("name").Distinct().From("users").Limit(5)).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM (SELECT DISTINCT name FROM users) AS t", sql)
+
+	sql, _, err = CountOf(Select("a").From("t1").Union().Columns("a").From("t2").OrderBy("a")).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM (SELECT a FROM t1 UNION SELECT a FROM t2) AS t", sql)
+}
@@ -3,11 +3,12 @@ package sq
 import (
 	"bytes"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
 // DeleteBuilder builds SQL DELETE statements.
+//
+// Like SelectBuilder, DeleteBuilder is immutable.
 type DeleteBuilder interface {
 	// Prefix adds an expression to the beginning of the query.
 	Prefix(sql string, args ...interface{}) DeleteBuilder
@@ -18,29 +19,70 @@ type DeleteBuilder interface {
 	// Where adds WHERE expressions to the query.
 	Where(pred interface{}, args ...interface{}) DeleteBuilder
 
-	// OrderBy adds ORDER BY expressions to the query.
+	// OrderBy adds ORDER BY expressions to the query. It requires Limit or
+	// Offset, see Limit.
 	OrderBy(orderBys ...string) DeleteBuilder
 
+	// OrderByClause adds an ORDER BY expression to the query.
+	//
+	// See SelectBuilder.OrderByClause.
+	OrderByClause(pred interface{}, args ...interface{}) DeleteBuilder
+
 	// Limit sets a LIMIT clause on the query.
+	//
+	// PostgreSQL does not support ORDER BY, LIMIT and OFFSET in DELETE
+	// statements, so they are emulated by restricting the WHERE clause to the
+	// rows selected by a subquery, identified by their ctid or the LimitKey
+	// columns:
+	//
+	//   Delete("t").Where("b").OrderBy("c").Limit(10) ==
+	//     "DELETE FROM t WHERE ctid IN (SELECT ctid FROM t WHERE b ORDER BY c LIMIT 10)"
 	Limit(limit uint64) DeleteBuilder
 
 	// Offset sets a OFFSET clause on the query.
+	//
+	// See Limit.
 	Offset(offset uint64) DeleteBuilder
 
+	// LimitKey sets the columns identifying rows when emulating ORDER BY, LIMIT
+	// and OFFSET, which defaults to ctid. A primary key should be used for
+	// partitioned tables, where ctid is not unique.
+	//
+	// See Limit.
+	LimitKey(columns ...string) DeleteBuilder
+
 	// Suffix adds an expression to the end of the query
 	Suffix(sql string, args ...interface{}) DeleteBuilder
 
+	// Using adds a USING clause to the query, making the columns of other
+	// tables available in the WHERE clause. Using accepts a StatementBuilder such
+	// as a subquery or a VALUES list, or args which will be bound to
+	// placeholders in the using string.
+	//
+	//   Using("orders o").Where("o.user_id = users.id AND o.status = ?", "void")
+	Using(using interface{}, args ...interface{}) DeleteBuilder
+
 	// JoinClause adds a join clause to the query.
-	JoinClause(join string) DeleteBuilder
+	//
+	// PostgreSQL does not support joins in DELETE statements, so only inner
+	// joins with an ON condition are supported, which are rendered as a USING
+	// table and a WHERE condition. Other joins return an error from ToSQL.
+	JoinClause(join string, args ...interface{}) DeleteBuilder
 
 	// Join adds a JOIN clause to the query.
-	Join(join string) DeleteBuilder
+	//
+	// See JoinClause.
+	Join(join string, args ...interface{}) DeleteBuilder
 
 	// LeftJoin adds a LEFT JOIN clause to the query.
-	LeftJoin(join string) DeleteBuilder
+	//
+	// See JoinClause.
+	LeftJoin(join string, args ...interface{}) DeleteBuilder
 
 	// RightJoin adds a RIGHT JOIN clause to the query.
-	RightJoin(join string) DeleteBuilder
+	//
+	// See JoinClause.
+	RightJoin(join string, args ...interface{}) DeleteBuilder
 
 	ToSQL() (sqlStr string, args []interface{}, err error)
 }
@@ -48,14 +90,16 @@ type DeleteBuilder interface {
 type deleteBuilder struct {
 	prefixes   exprs
 	from       string
-	joins      []string
+	usings     []StatementBuilder
+	joins      exprs
 	whereParts []StatementBuilder
-	orderBys   []string
+	orderBys   []StatementBuilder
 
 	limit       uint64
 	limitValid  bool
 	offset      uint64
 	offsetValid bool
+	limitKeys   []string
 
 	suffixes exprs
 }
@@ -65,6 +109,18 @@ func NewDeleteBuilder() DeleteBuilder {
 	return &deleteBuilder{}
 }
 
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *deleteBuilder) clone() *deleteBuilder {
+	nb := *b
+	nb.prefixes = nb.prefixes[:len(nb.prefixes):len(nb.prefixes)]
+	nb.usings = nb.usings[:len(nb.usings):len(nb.usings)]
+	nb.joins = nb.joins[:len(nb.joins):len(nb.joins)]
+	nb.whereParts = nb.whereParts[:len(nb.whereParts):len(nb.whereParts)]
+	nb.orderBys = nb.orderBys[:len(nb.orderBys):len(nb.orderBys)]
+	nb.suffixes = nb.suffixes[:len(nb.suffixes):len(nb.suffixes)]
+	return &nb
+}
+
 func (b *deleteBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	if len(b.from) == 0 {
 		err = fmt.Errorf("delete statements must specify a From table")
@@ -74,7 +130,10 @@ func (b *deleteBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSQL(sql, " ", args)
+		args, err = b.prefixes.AppendToSQL(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -82,38 +141,59 @@ func (b *deleteBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	sql.WriteString("FROM ")
 	sql.WriteString(b.from)
 
+	usings := b.usings
+	whereParts := b.whereParts
 	if len(b.joins) > 0 {
-		sql.WriteString(" ")
-		sql.WriteString(strings.Join(b.joins, " "))
+		var joinUsings, joinWheres []StatementBuilder
+		for _, j := range b.joins {
+			var using, where StatementBuilder
+			using, where, err = joinToUsing(j)
+			if err != nil {
+				return
+			}
+			joinUsings = append(joinUsings, using)
+			joinWheres = append(joinWheres, where)
+		}
+		usings = append(usings[:len(usings):len(usings)], joinUsings...)
+		whereParts = append(joinWheres, whereParts...)
 	}
 
-	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSQL(b.whereParts, sql, " AND ", args)
-		if err != nil {
+	if len(b.orderBys) > 0 && !b.limitValid && !b.offsetValid {
+		err = fmt.Errorf("delete statements with ORDER BY must also have LIMIT or OFFSET")
+		return
+	}
+	if b.limitValid || b.offsetValid {
+		if len(usings) > 0 {
+			err = fmt.Errorf("delete statements with ORDER BY, LIMIT or OFFSET cannot have USING or JOIN clauses")
 			return
 		}
-	}
 
-	if len(b.orderBys) > 0 {
-		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		whereParts = []StatementBuilder{limitPredicate(b.from, b.limitKeys,
+			limitSelect(whereParts, b.orderBys, b.limit, b.limitValid, b.offset, b.offsetValid))}
 	}
 
-	// TODO: limit == 0 and offset == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+	if len(usings) > 0 {
+		sql.WriteString(" USING ")
+		args, err = appendToSQL(usings, sql, ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+	if len(whereParts) > 0 {
+		sql.WriteString(" WHERE ")
+		args, err = appendToSQL(whereParts, sql, " AND ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSQL(sql, " ", args)
+		args, err = b.suffixes.AppendToSQL(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	sqlStr = sql.String()
@@ -121,58 +201,124 @@ func (b *deleteBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 }
 
 func (b *deleteBuilder) Prefix(sql string, args ...interface{}) DeleteBuilder {
+	b = b.clone()
 	b.prefixes = append(b.prefixes, expr{sql: sql, args: args})
 	return b
 }
 
 func (b *deleteBuilder) From(from string) DeleteBuilder {
+	b = b.clone()
 	b.from = from
 	return b
 }
 
 func (b *deleteBuilder) Where(pred interface{}, args ...interface{}) DeleteBuilder {
+	b = b.clone()
 	b.whereParts = append(b.whereParts, newWherePart(pred, args...))
 	return b
 }
 
 func (b *deleteBuilder) OrderBy(orderBys ...string) DeleteBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	b = b.clone()
+	for _, str := range orderBys {
+		b.orderBys = append(b.orderBys, newPart(str))
+	}
+	return b
+}
+
+func (b *deleteBuilder) OrderByClause(pred interface{}, args ...interface{}) DeleteBuilder {
+	b = b.clone()
+	b.orderBys = append(b.orderBys, newPart(pred, args...))
 	return b
 }
 
 func (b *deleteBuilder) Limit(limit uint64) DeleteBuilder {
+	b = b.clone()
 	b.limit = limit
 	b.limitValid = true
 	return b
 }
 
 func (b *deleteBuilder) Offset(offset uint64) DeleteBuilder {
+	b = b.clone()
 	b.offset = offset
 	b.offsetValid = true
 
 	return b
 }
 
+func (b *deleteBuilder) LimitKey(columns ...string) DeleteBuilder {
+	b = b.clone()
+	b.limitKeys = columns
+	return b
+}
+
 func (b *deleteBuilder) Suffix(sql string, args ...interface{}) DeleteBuilder {
+	b = b.clone()
 	b.suffixes = append(b.suffixes, expr{sql: sql, args: args})
 
 	return b
 }
 
-func (b *deleteBuilder) JoinClause(join string) DeleteBuilder {
-	b.joins = append(b.joins, join)
+func (b *deleteBuilder) Using(using interface{}, args ...interface{}) DeleteBuilder {
+	b = b.clone()
+	b.usings = append(b.usings, newPart(using, args...))
+	return b
+}
+
+func (b *deleteBuilder) JoinClause(join string, args ...interface{}) DeleteBuilder {
+	b = b.clone()
+	b.joins = append(b.joins, expr{sql: join, args: args})
 
 	return b
 }
 
-func (b *deleteBuilder) Join(join string) DeleteBuilder {
-	return b.JoinClause("JOIN " + join)
+func (b *deleteBuilder) Join(join string, args ...interface{}) DeleteBuilder {
+	return b.JoinClause("JOIN "+join, args...)
+}
+
+func (b *deleteBuilder) LeftJoin(join string, args ...interface{}) DeleteBuilder {
+	return b.JoinClause("LEFT JOIN "+join, args...)
 }
 
-func (b *deleteBuilder) LeftJoin(join string) DeleteBuilder {
-	return b.JoinClause("LEFT JOIN " + join)
+func (b *deleteBuilder) RightJoin(join string, args ...interface{}) DeleteBuilder {
+	return b.JoinClause("RIGHT JOIN "+join, args...)
 }
 
-func (b *deleteBuilder) RightJoin(join string) DeleteBuilder {
-	return b.JoinClause("RIGHT JOIN " + join)
+// joinToUsing splits an inner join clause into a USING table and a WHERE
+// condition.
+func joinToUsing(join expr) (using StatementBuilder, where StatementBuilder, err error) {
+	sql, args, err := join.ToSQL()
+	if err != nil {
+		return
+	}
+
+	table := strings.TrimSpace(sql)
+	if k := topLevelKeyword(table, "INNER"); len(k) > 0 && k[0] == 0 {
+		table = strings.TrimSpace(table[len("INNER"):])
+	}
+	if k := topLevelKeyword(table, "JOIN"); len(k) > 0 && k[0] == 0 {
+		table = table[len("JOIN"):]
+	} else {
+		table = ""
+	}
+
+	ons := topLevelKeyword(table, "ON")
+	if len(ons) != 1 {
+		err = fmt.Errorf("delete statements only support joins of the form JOIN <table> ON <condition>, use Using instead of %q", sql)
+		return
+	}
+	i := ons[0]
+
+	n, err := countPlaceholders(table[:i])
+	if err != nil {
+		return
+	}
+	if n > len(args) {
+		n = len(args)
+	}
+
+	using = Expr(strings.TrimSpace(table[:i]), args[:n]...)
+	where = Expr(fmt.Sprintf("(%s)", strings.TrimSpace(table[i+len("ON"):])), args[n:]...)
+	return
 }
@@ -1,6 +1,7 @@
 package sq
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,7 +22,7 @@ func TestDeleteBuilderToSQL(t *testing.T) {
 
 	expectedSQL :=
 		"WITH prefix AS ? " +
-			"DELETE FROM a WHERE b = ? ORDER BY c LIMIT 2 OFFSET 3 " +
+			"DELETE FROM a WHERE ctid IN (SELECT ctid FROM a WHERE b = ? ORDER BY c LIMIT 2 OFFSET 3) " +
 			"RETURNING ?"
 	assert.Equal(t, expectedSQL, sql)
 
@@ -51,7 +52,7 @@ func TestDeleteBuilderZeroOffsetLimit(t *testing.T) {
 	sql, _, err := qb.ToSQL()
 	assert.NoError(t, err)
 
-	expectedSQL := "DELETE FROM b LIMIT 0 OFFSET 0"
+	expectedSQL := "DELETE FROM b WHERE ctid IN (SELECT ctid FROM b LIMIT 0 OFFSET 0)"
 	assert.Equal(t, expectedSQL, sql)
 }
 
@@ -59,3 +60,101 @@ func TestDeleteBuilderToSQLErr(t *testing.T) {
 	_, _, err := Delete("").ToSQL()
 	assert.Error(t, err)
 }
+
+func TestDeleteBuilderConcurrent(t *testing.T) {
+	base := Delete("a").Where("b = ?", 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.Where("c = ?", i).Suffix("RETURNING d").ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, "DELETE FROM a WHERE b = ? AND c = ? RETURNING d", sql)
+			assert.Equal(t, []interface{}{1, i}, args)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDeleteBuilderUsing(t *testing.T) {
+	qb := Delete("users").
+		Using("orders o").
+		Using(Values([]interface{}{1}, []interface{}{2}).As("v", "id")).
+		Where("o.user_id = users.id AND users.id = v.id AND o.status = ?", "void")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "DELETE FROM users " +
+		"USING orders o, (VALUES (?),(?)) AS v(id) " +
+		"WHERE o.user_id = users.id AND users.id = v.id AND o.status = ?"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{1, 2, "void"}, args)
+}
+
+func TestDeleteBuilderJoin(t *testing.T) {
+	qb := Delete("users").
+		Using("accounts a").
+		Join("(SELECT user_id FROM orders WHERE total > ?) o ON o.user_id = users.id AND o.kind = ?", 10, "x").
+		Where("a.id = users.account_id AND a.closed = ?", true)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "DELETE FROM users " +
+		"USING accounts a, (SELECT user_id FROM orders WHERE total > ?) o " +
+		"WHERE (o.user_id = users.id AND o.kind = ?) AND a.id = users.account_id AND a.closed = ?"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{10, "x", true}, args)
+
+	_, _, err = Delete("users").LeftJoin("orders o ON o.user_id = users.id").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Delete("users").Join("orders USING (id)").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Delete("users").Join("orders o ON o.user_id = users.id JOIN items i ON i.order_id = o.id").ToSQL()
+	assert.Error(t, err)
+}
+
+func TestDeleteBuilderJoinSubquery(t *testing.T) {
+	sql, _, err := Delete("users").
+		Join("(SELECT o.user_id FROM orders o JOIN items i ON i.order_id = o.id) x ON x.user_id = users.id").
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users "+
+		"USING (SELECT o.user_id FROM orders o JOIN items i ON i.order_id = o.id) x "+
+		"WHERE (x.user_id = users.id)", sql)
+
+	sql, _, err = Delete("users").Join("ıtems x on x.user_id = users.id AND x.name = 'on'").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users USING ıtems x WHERE (x.user_id = users.id AND x.name = 'on')", sql)
+}
+
+func TestDeleteBuilderJoinMultiline(t *testing.T) {
+	sql, args, err := Delete("users").
+		JoinClause("INNER\tJOIN orders o\n\tON o.user_id = users.id\n\tAND o.total > ?", 10).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users USING orders o WHERE (o.user_id = users.id\n\tAND o.total > ?)", sql)
+	assert.Equal(t, []interface{}{10}, args)
+}
+
+func TestDeleteBuilderLimit(t *testing.T) {
+	qb := Delete("a").
+		Where("b < ?", 1).
+		OrderByClause(Asc("c")).
+		Limit(1000).
+		LimitKey("id")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE id IN (SELECT id FROM a WHERE b < ? ORDER BY c ASC LIMIT 1000)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = qb.Using("d").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = qb.Join("d ON d.id = a.d_id").ToSQL()
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package sq
+
+import (
+	"errors"
+	"net"
+
+	"github.com/jackc/pgconn"
+)
+
+// Error is an error reported by the PostgreSQL server, which exposes the
+// error code along with fields such as ConstraintName, TableName, ColumnName,
+// Detail and Hint.
+type Error = pgconn.PgError
+
+// AsError returns the first PostgreSQL error in the chain of err.
+//
+//     if e, ok := AsError(err); ok && e.Code == CodeUniqueViolation && e.ConstraintName == "users_email_key" {
+//         ...
+//     }
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) && e != nil {
+		return e, true
+	}
+	return nil, false
+}
+
+// IsClass returns whether err is a PostgreSQL error in the class identified by
+// the first two characters of its code, such as "23" for integrity constraint
+// violations.
+func IsClass(err error, class string) bool {
+	e, ok := AsError(err)
+	return ok && len(e.Code) == 5 && e.Code[:2] == class
+}
+
+// IsIntegrityViolation returns whether err is an integrity constraint
+// violation, such as a unique, foreign key, not null or check violation.
+func IsIntegrityViolation(err error) bool {
+	return IsClass(err, CodeIntegrityConstraintViolation[:2])
+}
+
+// IsRetryable returns whether the transaction or statement that failed with
+// err can be retried, which is the case for serialization failures, deadlocks
+// and errors that occurred before anything was sent to the server.
+func IsRetryable(err error) bool {
+	return IsError(err, CodeSerializationFailure) ||
+		IsError(err, CodeDeadlockDetected) ||
+		pgconn.SafeToRetry(err)
+}
+
+// IsConnectionError returns whether err is caused by a failed or lost
+// connection to the server, including a server shutdown.
+func IsConnectionError(err error) bool {
+	if IsClass(err, CodeConnectionException[:2]) ||
+		IsError(err, CodeAdminShutdown) ||
+		IsError(err, CodeCrashShutdown) ||
+		IsError(err, CodeCannotConnectNow) {
+		return true
+	}
+
+	var e net.Error
+	return errors.As(err, &e)
+}
+
+// ConditionName returns the condition name of an error code, such as
+// "unique_violation" for CodeUniqueViolation, or an empty string if the code
+// is unknown.
+func ConditionName(code string) string {
+	return conditionNames[code]
+}
@@ -0,0 +1,59 @@
+package sq
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsError(t *testing.T) {
+	err := fmt.Errorf("insert user: %w", &Error{
+		Code:           CodeUniqueViolation,
+		ConstraintName: "users_email_key",
+		TableName:      "users",
+		Detail:         "Key (email)=(a@example.com) already exists.",
+	})
+
+	e, ok := AsError(err)
+	if assert.True(t, ok) {
+		assert.Equal(t, CodeUniqueViolation, e.Code)
+		assert.Equal(t, "users_email_key", e.ConstraintName)
+		assert.Equal(t, "users", e.TableName)
+	}
+
+	_, ok = AsError(errors.New("unique violation"))
+	assert.False(t, ok)
+
+	_, ok = AsError(nil)
+	assert.False(t, ok)
+}
+
+func TestErrorPredicates(t *testing.T) {
+	unique := &Error{Code: CodeUniqueViolation}
+	assert.True(t, IsClass(unique, "23"))
+	assert.False(t, IsClass(unique, "40"))
+	assert.True(t, IsIntegrityViolation(unique))
+	assert.False(t, IsRetryable(unique))
+	assert.False(t, IsConnectionError(unique))
+
+	assert.True(t, IsRetryable(&Error{Code: CodeSerializationFailure}))
+	assert.True(t, IsRetryable(fmt.Errorf("tx: %w", &Error{Code: CodeDeadlockDetected})))
+	assert.False(t, IsIntegrityViolation(&Error{Code: CodeDeadlockDetected}))
+
+	assert.True(t, IsConnectionError(&Error{Code: CodeConnectionFailure}))
+	assert.True(t, IsConnectionError(&Error{Code: CodeAdminShutdown}))
+	assert.True(t, IsConnectionError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	assert.False(t, IsConnectionError(errors.New("other")))
+	assert.False(t, IsConnectionError(nil))
+}
+
+func TestConditionName(t *testing.T) {
+	assert.Equal(t, "unique_violation", ConditionName(CodeUniqueViolation))
+	assert.Equal(t, "serialization_failure", ConditionName(CodeSerializationFailure))
+	assert.Equal(t, "sqlclient_unable_to_establish_sqlconnection", ConditionName(CodeSQLClientUnableToEstablishSqlconnection))
+	assert.Equal(t, "invalid_sql_json_subscript", ConditionName(CodeInvalidSQLJSONSubscript))
+	assert.Equal(t, "", ConditionName("ZZZZZ"))
+}
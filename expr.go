@@ -32,7 +32,7 @@ func (e expr) ToSQL() (string, []interface{}, error) {
 	}
 
 	args := make([]interface{}, 0, len(e.args))
-	sql, err := replacePlaceholdersIter(e.sql, func(buf *bytes.Buffer, i int) error {
+	sql, err := replacePlaceholdersIter(e.sql, true, func(buf *bytes.Buffer, i int) error {
 		if i > len(e.args) {
 			buf.WriteRune('?')
 			return nil
@@ -136,6 +136,16 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []interface{}, err error) {
 
 		if val == nil {
 			expr = fmt.Sprintf("%s %s NULL", key, nullOpr)
+		} else if v, ok := val.(arrayExpr); ok {
+			var s string
+			var a []interface{}
+			s, a, err = v.ToSQL()
+			if err != nil {
+				return
+			}
+
+			expr = fmt.Sprintf("%s %s %s", key, equalOpr, s)
+			args = append(args, a...)
 		} else if v, ok := val.(StatementBuilder); ok {
 			var s string
 			var a []interface{}
@@ -270,6 +280,231 @@ func (gtOrEq GtOrEq) ToSQL() (sql string, args []interface{}, err error) {
 	return Lt(gtOrEq).toSQL(true, true)
 }
 
+// Like is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(Like{"name": "%irrel"})
+//
+// Use EscapeLike to match user input literally.
+type Like map[string]interface{}
+
+func (lk Like) toSQL(opposite, insensitive bool) (sql string, args []interface{}, err error) {
+	var (
+		exprs []string
+		opr   = "LIKE"
+	)
+
+	if insensitive {
+		opr = "ILIKE"
+	}
+
+	if opposite {
+		opr = fmt.Sprintf("NOT %s", opr)
+	}
+
+	for key, val := range lk {
+		expr := ""
+
+		switch v := val.(type) {
+		case driver.Valuer:
+			if val, err = v.Value(); err != nil {
+				return
+			}
+		}
+
+		if val == nil {
+			err = fmt.Errorf("cannot use null with like operators")
+			return
+		} else if v, ok := val.(StatementBuilder); ok {
+			var s string
+			var a []interface{}
+			s, a, err = v.ToSQL()
+			if err != nil {
+				return
+			}
+
+			expr = fmt.Sprintf("%s %s %s", key, opr, s)
+			args = append(args, a...)
+		} else {
+			valVal := reflect.ValueOf(val)
+			if valVal.Kind() == reflect.Array || valVal.Kind() == reflect.Slice {
+				err = fmt.Errorf("cannot use array or slice with like operators")
+				return
+			}
+			expr = fmt.Sprintf("%s %s ?", key, opr)
+			args = append(args, val)
+		}
+		exprs = append(exprs, expr)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+func (lk Like) ToSQL() (sql string, args []interface{}, err error) {
+	return lk.toSQL(false, false)
+}
+
+// NotLike is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(NotLike{"name": "%irrel"}) == "name NOT LIKE '%irrel'"
+type NotLike Like
+
+func (nlk NotLike) ToSQL() (sql string, args []interface{}, err error) {
+	return Like(nlk).toSQL(true, false)
+}
+
+// ILike is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(ILike{"name": "sq%"}) == "name ILIKE 'sq%'"
+type ILike Like
+
+func (ilk ILike) ToSQL() (sql string, args []interface{}, err error) {
+	return Like(ilk).toSQL(false, true)
+}
+
+// NotILike is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(NotILike{"name": "sq%"}) == "name NOT ILIKE 'sq%'"
+type NotILike Like
+
+func (nilk NotILike) ToSQL() (sql string, args []interface{}, err error) {
+	return Like(nilk).toSQL(true, true)
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the LIKE wildcards in s so that it matches literally
+// when used as part of a Like, NotLike, ILike or NotILike pattern.
+//
+//     .Where(ILike{"name": "%" + EscapeLike(search) + "%"})
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// Between is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(Between{"age": {18, 65}}) == "age BETWEEN 18 AND 65"
+type Between map[string][2]interface{}
+
+func (bt Between) toSQL(opposite bool) (sql string, args []interface{}, err error) {
+	var (
+		exprs []string
+		opr   = "BETWEEN"
+	)
+
+	if opposite {
+		opr = "NOT BETWEEN"
+	}
+
+	for key, bounds := range bt {
+		boundSQLs := make([]string, len(bounds))
+
+		for i, val := range bounds {
+			switch v := val.(type) {
+			case driver.Valuer:
+				if val, err = v.Value(); err != nil {
+					return
+				}
+			}
+
+			if val == nil {
+				err = fmt.Errorf("cannot use null with between operators")
+				return
+			} else if v, ok := val.(StatementBuilder); ok {
+				var s string
+				var a []interface{}
+				s, a, err = v.ToSQL()
+				if err != nil {
+					return
+				}
+
+				boundSQLs[i] = s
+				args = append(args, a...)
+			} else {
+				boundSQLs[i] = "?"
+				args = append(args, val)
+			}
+		}
+		exprs = append(exprs, fmt.Sprintf("%s %s %s AND %s", key, opr, boundSQLs[0], boundSQLs[1]))
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+func (bt Between) ToSQL() (sql string, args []interface{}, err error) {
+	return bt.toSQL(false)
+}
+
+// NotBetween is syntactic sugar for use with Where/Having/Set methods.
+//
+//     .Where(NotBetween{"age": {18, 65}}) == "age NOT BETWEEN 18 AND 65"
+type NotBetween Between
+
+func (nbt NotBetween) ToSQL() (sql string, args []interface{}, err error) {
+	return Between(nbt).toSQL(true)
+}
+
+// IsDistinctFrom is syntactic sugar for use with Where/Having/Set methods.
+// Unlike NotEq, it treats NULL as a comparable value.
+//
+//     .Where(IsDistinctFrom{"id": 1}) == "id IS DISTINCT FROM 1"
+type IsDistinctFrom map[string]interface{}
+
+func (d IsDistinctFrom) toSQL(opposite bool) (sql string, args []interface{}, err error) {
+	var (
+		exprs []string
+		opr   = "IS DISTINCT FROM"
+	)
+
+	if opposite {
+		opr = "IS NOT DISTINCT FROM"
+	}
+
+	for key, val := range d {
+		expr := ""
+
+		switch v := val.(type) {
+		case driver.Valuer:
+			if val, err = v.Value(); err != nil {
+				return
+			}
+		}
+
+		if val == nil {
+			expr = fmt.Sprintf("%s %s NULL", key, opr)
+		} else if v, ok := val.(StatementBuilder); ok {
+			var s string
+			var a []interface{}
+			s, a, err = v.ToSQL()
+			if err != nil {
+				return
+			}
+
+			expr = fmt.Sprintf("%s %s %s", key, opr, s)
+			args = append(args, a...)
+		} else {
+			expr = fmt.Sprintf("%s %s ?", key, opr)
+			args = append(args, val)
+		}
+		exprs = append(exprs, expr)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+func (d IsDistinctFrom) ToSQL() (sql string, args []interface{}, err error) {
+	return d.toSQL(false)
+}
+
+// IsNotDistinctFrom is syntactic sugar for use with Where/Having/Set methods.
+// Unlike Eq, it treats NULL as a comparable value.
+//
+//     .Where(IsNotDistinctFrom{"id": nil}) == "id IS NOT DISTINCT FROM NULL"
+type IsNotDistinctFrom IsDistinctFrom
+
+func (nd IsNotDistinctFrom) ToSQL() (sql string, args []interface{}, err error) {
+	return IsDistinctFrom(nd).toSQL(true)
+}
+
 type conj []StatementBuilder
 
 func (c conj) join(sep string) (sql string, args []interface{}, err error) {
@@ -309,6 +544,64 @@ func (o Or) ToSQL() (string, []interface{}, error) {
 	return conj(o).join(" OR ")
 }
 
+// Not is syntactic sugar that negates a where/having part.
+//
+//     .Where(Not{Like{"name": "sq%"}}) == "NOT (name LIKE 'sq%')"
+type Not struct {
+	Pred StatementBuilder
+}
+
+func (n Not) ToSQL() (sql string, args []interface{}, err error) {
+	if n.Pred == nil {
+		return
+	}
+	sql, args, err = n.Pred.ToSQL()
+	if err != nil || sql == "" {
+		return
+	}
+	sql = fmt.Sprintf("NOT (%s)", sql)
+	return
+}
+
+// Exists is syntactic sugar for use with Where/Having methods.
+//
+//     .Where(Exists{Select("1").From("b").Where("b.a_id = a.id")})
+type Exists struct {
+	Query StatementBuilder
+}
+
+func (e Exists) toSQL(opposite bool) (sql string, args []interface{}, err error) {
+	if e.Query == nil {
+		err = fmt.Errorf("exists operators must have a query")
+		return
+	}
+
+	sql, args, err = e.Query.ToSQL()
+	if err != nil {
+		return
+	}
+
+	if opposite {
+		sql = fmt.Sprintf("NOT EXISTS (%s)", sql)
+	} else {
+		sql = fmt.Sprintf("EXISTS (%s)", sql)
+	}
+	return
+}
+
+func (e Exists) ToSQL() (sql string, args []interface{}, err error) {
+	return e.toSQL(false)
+}
+
+// NotExists is syntactic sugar for use with Where/Having methods.
+//
+//     .Where(NotExists{Select("1").From("b").Where("b.a_id = a.id")})
+type NotExists Exists
+
+func (ne NotExists) ToSQL() (sql string, args []interface{}, err error) {
+	return Exists(ne).toSQL(true)
+}
+
 func hasQueryBuilder(args []interface{}) bool {
 	for _, arg := range args {
 		_, ok := arg.(StatementBuilder)
@@ -197,3 +197,121 @@ func TestExprQueryBuilder(t *testing.T) {
 		assert.Equal(t, []interface{}{42, 42}, args)
 	}
 }
+
+func TestLikeToSQL(t *testing.T) {
+	b := Like{"name": "%irrel"}
+	sql, args, err := b.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "name LIKE ?"
+	assert.Equal(t, expectedSQL, sql)
+
+	expectedArgs := []interface{}{"%irrel"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestNotLikeToSQL(t *testing.T) {
+	sql, args, err := NotLike{"name": "%irrel"}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "name NOT LIKE ?", sql)
+	assert.Equal(t, []interface{}{"%irrel"}, args)
+
+	sql, args, err = ILike{"name": "sq%"}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "name ILIKE ?", sql)
+	assert.Equal(t, []interface{}{"sq%"}, args)
+
+	sql, args, err = NotILike{"name": Expr("lower(?)", "sq%")}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "name NOT ILIKE lower(?)", sql)
+	assert.Equal(t, []interface{}{"sq%"}, args)
+}
+
+func TestLikeToSQLErr(t *testing.T) {
+	_, _, err := Like{"name": nil}.ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Like{"name": []string{"a"}}.ToSQL()
+	assert.Error(t, err)
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\% \_a\\b`, EscapeLike(`100% _a\b`))
+}
+
+func TestBetweenToSQL(t *testing.T) {
+	sql, args, err := Between{"age": {18, Expr("? + 47", 18)}}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "age BETWEEN ? AND ? + 47", sql)
+	assert.Equal(t, []interface{}{18, 18}, args)
+
+	sql, args, err = NotBetween{"age": {18, 65}}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "age NOT BETWEEN ? AND ?", sql)
+	assert.Equal(t, []interface{}{18, 65}, args)
+
+	_, _, err = Between{"age": {nil, 65}}.ToSQL()
+	assert.Error(t, err)
+}
+
+func TestIsDistinctFromToSQL(t *testing.T) {
+	sql, args, err := IsDistinctFrom{"id": 1}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "id IS DISTINCT FROM ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = IsNotDistinctFrom{"id": nil}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "id IS NOT DISTINCT FROM NULL", sql)
+	assert.Empty(t, args)
+}
+
+func TestNotToSQL(t *testing.T) {
+	sql, args, err := Not{Or{Eq{"a": 1}, Expr("b")}}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT ((a = ? OR b))", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Not{And{}}.ToSQL()
+	assert.NoError(t, err)
+	assert.Empty(t, sql)
+	assert.Empty(t, args)
+}
+
+func TestExistsToSQL(t *testing.T) {
+	qb := Select("a").
+		From("t").
+		Where(Exists{Select("1").From("u").Where("u.t_id = t.id AND u.x = ?", 1)}).
+		Having(NotExists{Select("1").From("v").Where(Eq{"v.y": 2})})
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT a FROM t " +
+		"WHERE EXISTS (SELECT 1 FROM u WHERE u.t_id = t.id AND u.x = ?) " +
+		"HAVING NOT EXISTS (SELECT 1 FROM v WHERE v.y = ?)"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	_, _, err = Exists{}.ToSQL()
+	assert.Error(t, err)
+}
+
+func TestPredicateInCase(t *testing.T) {
+	sql, args, err := Case().
+		When(ILike{"name": "a%"}, "1").
+		When(Between{"age": {1, 2}}, "2").
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "CASE WHEN name ILIKE ? THEN 1 WHEN age BETWEEN ? AND ? THEN 2 END", sql)
+	assert.Equal(t, []interface{}{"a%", 1, 2}, args)
+}
+
+func TestExprQueryBuilderEscape(t *testing.T) {
+	b := Expr("data ?? ? AND id IN (?)", "key", Select("id").From("t").Where("x = ?", 1))
+	sql, args, err := b.ToSQL()
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "data ?? ? AND id IN (SELECT id FROM t WHERE x = ?)", sql)
+		assert.Equal(t, []interface{}{"key", 1}, args)
+	}
+}
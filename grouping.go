@@ -0,0 +1,125 @@
+package sq
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// groupingExpr builds ROLLUP, CUBE and GROUPING SETS grouping elements.
+type groupingExpr struct {
+	fn    string
+	exprs []interface{}
+	sets  bool
+}
+
+func (g groupingExpr) ToSQL() (sqlStr string, args []interface{}, err error) {
+	if len(g.exprs) == 0 {
+		err = fmt.Errorf("%s must have at least one expression", g.fn)
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString(g.fn)
+	sql.WriteString(" (")
+	for i, e := range g.exprs {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		if g.sets && e == nil {
+			sql.WriteString("()")
+			continue
+		}
+		args, err = appendGroupingElem(sql, e, args)
+		if err != nil {
+			return
+		}
+	}
+	sql.WriteString(")")
+
+	sqlStr = sql.String()
+	return
+}
+
+// appendGroupingElem writes a single grouping element, which is a string, a
+// StatementBuilder or a slice of those rendered as a parenthesized list.
+func appendGroupingElem(sql *bytes.Buffer, e interface{}, args []interface{}) ([]interface{}, error) {
+	switch v := e.(type) {
+	case string:
+		sql.WriteString(v)
+	case StatementBuilder:
+		s, a, err := v.ToSQL()
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(s)
+		args = append(args, a...)
+	case []string, []interface{}:
+		return appendGroupingList(sql, v, args)
+	default:
+		return nil, fmt.Errorf("expected string, StatementBuilder or slice grouping element, not %T", e)
+	}
+	return args, nil
+}
+
+// appendGroupingList writes the slice e as a parenthesized list of grouping
+// elements.
+func appendGroupingList(sql *bytes.Buffer, e interface{}, args []interface{}) ([]interface{}, error) {
+	var elems []interface{}
+	switch v := e.(type) {
+	case []interface{}:
+		elems = v
+	case []string:
+		for _, s := range v {
+			elems = append(elems, s)
+		}
+	}
+
+	var err error
+	sql.WriteString("(")
+	for i, elem := range elems {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		args, err = appendGroupingElem(sql, elem, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sql.WriteString(")")
+	return args, nil
+}
+
+// Grouping builds a GROUPING expression for use with ROLLUP, CUBE and
+// GROUPING SETS, returning a bit mask of which columns are aggregated in the
+// current row.
+//
+//     .Column(Alias(Grouping("region", "product"), "level"))
+func Grouping(columns ...string) StatementBuilder {
+	exprs := make([]interface{}, len(columns))
+	for i, c := range columns {
+		exprs[i] = c
+	}
+	return groupingFunc{exprs: exprs}
+}
+
+type groupingFunc struct {
+	exprs []interface{}
+}
+
+func (g groupingFunc) ToSQL() (sqlStr string, args []interface{}, err error) {
+	if len(g.exprs) == 0 {
+		err = fmt.Errorf("grouping expressions must have at least one column")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString("GROUPING")
+	args, err = appendGroupingList(sql, g.exprs, args)
+	if err != nil {
+		return
+	}
+
+	sqlStr = sql.String()
+	return
+}
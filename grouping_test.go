@@ -0,0 +1,60 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByRollup(t *testing.T) {
+	qb := Select("region", "product", "sum(amount)").
+		Column(Alias(Grouping("region", "product"), "level")).
+		From("sales").
+		Where("year = ?", 2020).
+		GroupByRollup("region", []string{"country", "city"}, Expr("date_trunc(?, sold_at)", "month")).
+		Having(Gt{"sum(amount)": 10}).
+		OrderByClause(Grouping("region"))
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT region, product, sum(amount), (GROUPING(region, product)) AS level " +
+		"FROM sales WHERE year = ? " +
+		"GROUP BY ROLLUP (region, (country, city), date_trunc(?, sold_at)) " +
+		"HAVING sum(amount) > ? " +
+		"ORDER BY GROUPING(region)"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{2020, "month", 10}, args)
+}
+
+func TestGroupByCube(t *testing.T) {
+	sql, _, err := Select("a", "b", "count(*)").From("t").GroupBy("c").GroupByCube("a", "b").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b, count(*) FROM t GROUP BY c, CUBE (a, b)", sql)
+}
+
+func TestGroupingSets(t *testing.T) {
+	qb := Select("a", "b", "count(*)").
+		From("t").
+		GroupingSets([]string{"a", "b"}, "a", []interface{}{Expr("lower(?)", "b")}, nil)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b, count(*) FROM t GROUP BY GROUPING SETS ((a, b), a, (lower(?)), ())", sql)
+	assert.Equal(t, []interface{}{"b"}, args)
+}
+
+func TestGroupingErr(t *testing.T) {
+	_, _, err := Select("a").From("t").GroupByRollup(1).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Grouping().ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("t").GroupByRollup().ToSQL()
+	assert.EqualError(t, err, "ROLLUP must have at least one expression")
+
+	_, _, err = Select("a").From("t").GroupByCube().ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("t").GroupingSets().ToSQL()
+	assert.Error(t, err)
+}
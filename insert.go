@@ -7,6 +7,8 @@ import (
 )
 
 // InsertBuilder builds SQL INSERT statements.
+//
+// Like SelectBuilder, InsertBuilder is immutable.
 type InsertBuilder interface {
 	// Prefix adds an expression to the beginning of the query.
 	Prefix(sql string, args ...interface{}) InsertBuilder
@@ -47,6 +49,17 @@ func NewInsertBuilder() InsertBuilder {
 	return &insertBuilder{}
 }
 
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *insertBuilder) clone() *insertBuilder {
+	nb := *b
+	nb.prefixes = nb.prefixes[:len(nb.prefixes):len(nb.prefixes)]
+	nb.options = nb.options[:len(nb.options):len(nb.options)]
+	nb.columns = nb.columns[:len(nb.columns):len(nb.columns)]
+	nb.values = nb.values[:len(nb.values):len(nb.values)]
+	nb.suffixes = nb.suffixes[:len(nb.suffixes):len(nb.suffixes)]
+	return &nb
+}
+
 func (b *insertBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	if len(b.into) == 0 {
 		err = fmt.Errorf("insert statements must specify a table")
@@ -60,7 +73,10 @@ func (b *insertBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSQL(sql, " ", args)
+		args, err = b.prefixes.AppendToSQL(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -83,37 +99,17 @@ func (b *insertBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 	sql.WriteString("VALUES ")
 
-	valuesStrings := make([]string, len(b.values))
-	for r, row := range b.values {
-
-		valueStrings := make([]string, len(row))
-
-		for v, val := range row {
-			switch typedVal := val.(type) {
-			case StatementBuilder:
-				var valSQL string
-				var valArgs []interface{}
-
-				valSQL, valArgs, err = typedVal.ToSQL()
-				if err != nil {
-					return
-				}
-
-				valueStrings[v] = valSQL
-				args = append(args, valArgs...)
-			default:
-				valueStrings[v] = "?"
-				args = append(args, val)
-			}
-		}
-
-		valuesStrings[r] = fmt.Sprintf("(%s)", strings.Join(valueStrings, ","))
+	args, err = appendValuesToSQL(sql, b.values, nil, args)
+	if err != nil {
+		return
 	}
-	sql.WriteString(strings.Join(valuesStrings, ","))
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSQL(sql, " ", args)
+		args, err = b.suffixes.AppendToSQL(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	sqlStr = sql.String()
@@ -121,36 +117,43 @@ func (b *insertBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 }
 
 func (b *insertBuilder) Prefix(sql string, args ...interface{}) InsertBuilder {
+	b = b.clone()
 	b.prefixes = append(b.prefixes, expr{sql: sql, args: args})
 	return b
 }
 
 func (b *insertBuilder) Options(options ...string) InsertBuilder {
+	b = b.clone()
 	b.options = append(b.options, options...)
 	return b
 }
 
 func (b *insertBuilder) Into(into string) InsertBuilder {
+	b = b.clone()
 	b.into = into
 	return b
 }
 
 func (b *insertBuilder) Columns(columns ...string) InsertBuilder {
+	b = b.clone()
 	b.columns = append(b.columns, columns...)
 	return b
 }
 
 func (b *insertBuilder) Values(values ...interface{}) InsertBuilder {
+	b = b.clone()
 	b.values = append(b.values, values)
 	return b
 }
 
 func (b *insertBuilder) Suffix(sql string, args ...interface{}) InsertBuilder {
+	b = b.clone()
 	b.suffixes = append(b.suffixes, expr{sql: sql, args: args})
 	return b
 }
 
 func (b *insertBuilder) SetMap(clauses map[string]interface{}) InsertBuilder {
+	b = b.clone()
 	// TODO: replace resetting previous values with extending existing ones?
 	cols := make([]string, 0, len(clauses))
 	vals := make([]interface{}, 0, len(clauses))
@@ -1,6 +1,7 @@
 package sq
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,3 +50,21 @@ func TestInsertBuilderSetMap(t *testing.T) {
 	expectedArgs := []interface{}{1}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestInsertBuilderConcurrent(t *testing.T) {
+	base := Insert("a").Columns("b", "c").Values(1, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.Values(3, i).Suffix("RETURNING b").ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, "INSERT INTO a (b,c) VALUES (?,?),(?,?) RETURNING b", sql)
+			assert.Equal(t, []interface{}{1, 2, 3, i}, args)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,66 @@
+// Package placeholder rewrites the ? placeholders of sq statements, for use by
+// sq and its test helpers.
+package placeholder
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Replace returns sql with ? placeholders replaced by $1, $2, ... and escaped
+// ?? placeholders unescaped, as done before executing a statement.
+func Replace(sql string) (string, error) {
+	return Iter(sql, false, func(buf *bytes.Buffer, i int) error {
+		buf.WriteString("$")
+		buf.WriteString(strconv.Itoa(i))
+		return nil
+	})
+}
+
+// Iter calls replace for each placeholder in sql. Escaped placeholders (??)
+// are unescaped unless keepEscapes is set, which is needed when the result
+// will itself be passed through Replace.
+func Iter(sql string, keepEscapes bool, replace func(buf *bytes.Buffer, i int) error) (string, error) {
+	buf := &bytes.Buffer{}
+	i := 0
+	for {
+		p := strings.Index(sql, "?")
+		if p == -1 {
+			break
+		}
+
+		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
+			buf.WriteString(sql[:p])
+			if keepEscapes {
+				buf.WriteString("??")
+			} else {
+				buf.WriteString("?")
+			}
+			if len(sql[p:]) == 1 {
+				break
+			}
+			sql = sql[p+2:]
+		} else {
+			i++
+			buf.WriteString(sql[:p])
+			if err := replace(buf, i); err != nil {
+				return "", err
+			}
+			sql = sql[p+1:]
+		}
+	}
+
+	buf.WriteString(sql)
+	return buf.String(), nil
+}
+
+// Count returns the number of placeholders in sql.
+func Count(sql string) (int, error) {
+	count := 0
+	_, err := Iter(sql, true, func(buf *bytes.Buffer, i int) error {
+		count = i
+		return nil
+	})
+	return count, err
+}
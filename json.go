@@ -0,0 +1,172 @@
+package sq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath returns the SQL for extracting the JSON value at path from column,
+// suitable for use as a Columns entry or an Eq key.
+//
+//     JSONPath("data", "a")      == "data->'a'"
+//     JSONPath("data", "a", 0)   == "data#>'{\"a\",\"0\"}'"
+//
+// Path elements must be strings (object keys) or ints (array indexes).
+func JSONPath(column string, path ...interface{}) string {
+	return jsonPath(column, path, false)
+}
+
+// JSONPathText is like JSONPath but extracts the value as text using the ->>
+// and #>> operators.
+//
+//     .Where(Eq{JSONPathText("data", "a", "b"): "c"})
+func JSONPathText(column string, path ...interface{}) string {
+	return jsonPath(column, path, true)
+}
+
+func jsonPath(column string, path []interface{}, text bool) string {
+	var sql strings.Builder
+	sql.WriteString(column)
+
+	switch len(path) {
+	case 0:
+		if text {
+			sql.WriteString("#>>'{}'")
+		}
+	case 1:
+		if text {
+			sql.WriteString("->>")
+		} else {
+			sql.WriteString("->")
+		}
+		if i, ok := path[0].(int); ok {
+			sql.WriteString(strconv.Itoa(i))
+		} else {
+			sql.WriteString(quoteLiteral(fmt.Sprint(path[0])))
+		}
+	default:
+		if text {
+			sql.WriteString("#>>")
+		} else {
+			sql.WriteString("#>")
+		}
+		elems := make([]string, len(path))
+		for i, p := range path {
+			elems[i] = `"` + arrayElemReplacer.Replace(fmt.Sprint(p)) + `"`
+		}
+		sql.WriteString(quoteLiteral("{" + strings.Join(elems, ",") + "}"))
+	}
+
+	return sql.String()
+}
+
+var arrayElemReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteLiteral quotes s as a SQL string literal, escaping placeholders so it
+// can be embedded in a query.
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	s = strings.ReplaceAll(s, "?", "??")
+	return "'" + s + "'"
+}
+
+// jsonValue is a value bound as JSON text.
+type jsonValue struct {
+	value interface{}
+}
+
+func (v jsonValue) ToSQL() (string, []interface{}, error) {
+	if sb, ok := v.value.(StatementBuilder); ok {
+		return sb.ToSQL()
+	}
+
+	data, err := json.Marshal(v.value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "?", []interface{}{string(data)}, nil
+}
+
+// JSONContains is syntactic sugar for use with Where/Having methods. Values
+// are encoded with encoding/json, use json.RawMessage for pre-encoded JSON.
+//
+//     .Where(JSONContains{"data": map[string]interface{}{"a": 1}}) == "data @> '{\"a\":1}'"
+type JSONContains map[string]interface{}
+
+func (c JSONContains) ToSQL() (sql string, args []interface{}, err error) {
+	m := make(map[string]interface{}, len(c))
+	for key, val := range c {
+		m[key] = jsonValue{val}
+	}
+	return binaryOpToSQL(m, "@>")
+}
+
+// JSONHasKey is syntactic sugar for use with Where/Having methods.
+//
+//     .Where(JSONHasKey{"data": "a"}) == "data ? 'a'"
+type JSONHasKey map[string]string
+
+func (h JSONHasKey) ToSQL() (sql string, args []interface{}, err error) {
+	m := make(map[string]interface{}, len(h))
+	for key, val := range h {
+		m[key] = val
+	}
+	return binaryOpToSQL(m, "??")
+}
+
+// JSONHasAnyKey is syntactic sugar for use with Where/Having methods. The keys
+// are bound as a single array parameter.
+//
+//     .Where(JSONHasAnyKey{"data": {"a", "b"}}) == "data ?| '{a,b}'"
+type JSONHasAnyKey map[string][]string
+
+func (h JSONHasAnyKey) ToSQL() (sql string, args []interface{}, err error) {
+	return jsonHasKeysToSQL(h, "??|")
+}
+
+// JSONHasAllKeys is syntactic sugar for use with Where/Having methods. The keys
+// are bound as a single array parameter.
+//
+//     .Where(JSONHasAllKeys{"data": {"a", "b"}}) == "data ?& '{a,b}'"
+type JSONHasAllKeys map[string][]string
+
+func (h JSONHasAllKeys) ToSQL() (sql string, args []interface{}, err error) {
+	return jsonHasKeysToSQL(h, "??&")
+}
+
+func jsonHasKeysToSQL(h map[string][]string, opr string) (sql string, args []interface{}, err error) {
+	m := make(map[string]interface{}, len(h))
+	for key, val := range h {
+		if val == nil {
+			val = []string{}
+		}
+		m[key] = val
+	}
+	return binaryOpToSQL(m, opr)
+}
+
+// jsonSet is a jsonb_set expression for use as an UpdateBuilder value.
+type jsonSet struct {
+	column string
+	path   []string
+	value  interface{}
+}
+
+func (s jsonSet) ToSQL() (sql string, args []interface{}, err error) {
+	if len(s.path) == 0 {
+		err = fmt.Errorf("jsonb_set must have a path")
+		return
+	}
+
+	valSQL, valArgs, err := jsonValue{s.value}.ToSQL()
+	if err != nil {
+		return
+	}
+
+	sql = fmt.Sprintf("jsonb_set(%s, ?, %s)", s.column, valSQL)
+	args = append([]interface{}{s.path}, valArgs...)
+	return
+}
@@ -0,0 +1,75 @@
+package sq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPath(t *testing.T) {
+	assert.Equal(t, "data", JSONPath("data"))
+	assert.Equal(t, "data->'a'", JSONPath("data", "a"))
+	assert.Equal(t, "data->0", JSONPath("data", 0))
+	assert.Equal(t, `data#>'{"a","0"}'`, JSONPath("data", "a", 0))
+	assert.Equal(t, "data->>'it''s'", JSONPathText("data", "it's"))
+	assert.Equal(t, `data#>>'{"a??","b\"c"}'`, JSONPathText("data", "a?", `b"c`))
+}
+
+func TestJSONPathSelect(t *testing.T) {
+	qb := Select(JSONPathText("data", "name")).
+		From("t").
+		Where(Eq{JSONPathText("data", "a", "b"): "c"}).
+		Where("x = ?", 1)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT data->>'name' FROM t WHERE data#>>'{"a","b"}' = ? AND x = ?`, sql)
+	assert.Equal(t, []interface{}{"c", 1}, args)
+
+	sql, err = replacePlaceholders(JSONPathText("data", "a?") + " = ?")
+	assert.NoError(t, err)
+	assert.Equal(t, "data->>'a?' = $1", sql)
+}
+
+func TestJSONContainsToSQL(t *testing.T) {
+	sql, args, err := JSONContains{"data": map[string]interface{}{"a": 1}}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "data @> ?", sql)
+	assert.Equal(t, []interface{}{`{"a":1}`}, args)
+
+	sql, args, err = JSONContains{"data": json.RawMessage(`{"b": true}`)}.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "data @> ?", sql)
+	assert.Equal(t, []interface{}{`{"b":true}`}, args)
+}
+
+func TestJSONHasKeyToSQL(t *testing.T) {
+	qb := Select("id").
+		From("t").
+		Where(JSONHasKey{"data": "a"}).
+		Where(JSONHasAnyKey{"data": {"b", "c"}}).
+		Where(JSONHasAllKeys{"data": {"d"}}).
+		Where("x = ?", 1)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE data ?? ? AND data ??| ? AND data ??& ? AND x = ?", sql)
+	assert.Equal(t, []interface{}{"a", []string{"b", "c"}, []string{"d"}, 1}, args)
+
+	sql, err = replacePlaceholders(sql)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE data ? $1 AND data ?| $2 AND data ?& $3 AND x = $4", sql)
+}
+
+func TestUpdateBuilderSetJSON(t *testing.T) {
+	qb := Update("t").
+		SetJSON("data", []string{"a", "b"}, "c").
+		SetJSON("meta", []string{"n"}, Expr("to_jsonb(?::int)", 1)).
+		Where("id = ?", 2)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET data = jsonb_set(data, ?, ?), meta = jsonb_set(meta, ?, to_jsonb(?::int)) WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{[]string{"a", "b"}, `"c"`, []string{"n"}, 1, 2}, args)
+
+	_, _, err = Update("t").SetJSON("data", nil, 1).ToSQL()
+	assert.Error(t, err)
+}
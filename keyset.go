@@ -0,0 +1,383 @@
+package sq
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidCursor is returned by Keyset.Select for malformed cursors.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortKey is a sort key for keyset pagination.
+type SortKey struct {
+	// Column is the expression to sort by.
+	Column string
+
+	// Field is the result column holding the key value, which defaults to
+	// Column without any table qualifier.
+	Field string
+
+	// Type is the SQL type the cursor value is cast to, such as timestamptz
+	// or uuid. Cursor values are decoded as strings, numbers or booleans, so
+	// keys of other types should set it.
+	Type string
+
+	// Desc sorts in descending order.
+	Desc bool
+
+	// Nullable handles NULL values, which are sorted first if NullsFirst is
+	// set and last otherwise.
+	Nullable   bool
+	NullsFirst bool
+}
+
+func (k SortKey) field() string {
+	if k.Field != "" {
+		return k.Field
+	}
+	if i := strings.LastIndex(k.Column, "."); i != -1 {
+		return k.Column[i+1:]
+	}
+	return k.Column
+}
+
+// placeholder returns the placeholder for the key value, with a cast to Type
+// if set.
+func (k SortKey) placeholder() string {
+	if k.Type != "" {
+		return "?::" + k.Type
+	}
+	return "?"
+}
+
+func (k SortKey) reverse() SortKey {
+	k.Desc = !k.Desc
+	k.NullsFirst = !k.NullsFirst
+	return k
+}
+
+func (k SortKey) orderBy() string {
+	sql := k.Column
+	if k.Desc {
+		sql += " DESC"
+	}
+	if k.Nullable {
+		if k.NullsFirst {
+			sql += " NULLS FIRST"
+		} else {
+			sql += " NULLS LAST"
+		}
+	}
+	return sql
+}
+
+// after returns a predicate matching rows sorted strictly after value on this
+// key, or nil if there are none.
+func (k SortKey) after(value interface{}) StatementBuilder {
+	opr := ">"
+	if k.Desc {
+		opr = "<"
+	}
+
+	switch {
+	case value == nil && k.NullsFirst:
+		return Expr(k.Column + " IS NOT NULL")
+	case value == nil:
+		return nil
+	case k.Nullable && !k.NullsFirst:
+		return Or{Expr(fmt.Sprintf("%s %s %s", k.Column, opr, k.placeholder()), value), Expr(k.Column + " IS NULL")}
+	default:
+		return Expr(fmt.Sprintf("%s %s %s", k.Column, opr, k.placeholder()), value)
+	}
+}
+
+func (k SortKey) equal(value interface{}) StatementBuilder {
+	if value == nil {
+		return Expr(k.Column + " IS NULL")
+	}
+	return Expr(k.Column+" = "+k.placeholder(), value)
+}
+
+// Keyset builds keyset (cursor) paginated queries, which unlike OFFSET
+// pagination perform the same for every page. The keys must uniquely identify
+// a row, typically by ending with the primary key.
+//
+//     ks := Keyset{Keys: []SortKey{{Column: "created_at", Type: "timestamptz", Desc: true}, {Column: "id", Desc: true}}, Limit: 20}
+//     qb, err := ks.Select(Select("id", "created_at").From("posts"), cursor)
+//     ...
+//     err = pool.All(ctx, qb, &posts)
+//     ...
+//     page, err := ks.Page(cursor, &posts)
+type Keyset struct {
+	Keys  []SortKey
+	Limit uint64
+}
+
+type keysetCursor struct {
+	Before bool          `json:"b,omitempty"`
+	Values []interface{} `json:"v"`
+}
+
+// Page holds the cursors for the pages adjacent to the current page, which
+// are empty if there is no such page.
+type Page struct {
+	Next string
+	Prev string
+}
+
+// Select returns qb restricted to the page identified by cursor, or the first
+// page if cursor is empty. Qb must not have ORDER BY, LIMIT or OFFSET clauses.
+//
+// The query selects one more row than Limit to determine if there are more
+// rows, see Page.
+func (k Keyset) Select(qb SelectBuilder, cursor string) (SelectBuilder, error) {
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	if b, ok := qb.(*selectBuilder); ok && (len(b.orderBys) > 0 || b.limit != nil || b.offset != nil || b.fetch != nil) {
+		return nil, fmt.Errorf("keyset pagination query must not have ORDER BY, LIMIT or OFFSET clauses")
+	}
+
+	c, err := k.decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := k.Keys
+	if c.Before {
+		keys = make([]SortKey, len(k.Keys))
+		for i, key := range k.Keys {
+			keys[i] = key.reverse()
+		}
+	}
+
+	if c.Values != nil {
+		qb = qb.Where(keysetPredicate(keys, c.Values))
+	}
+
+	for _, key := range keys {
+		qb = qb.OrderBy(key.orderBy())
+	}
+
+	return qb.Limit(k.Limit + 1), nil
+}
+
+func (k Keyset) validate() error {
+	if len(k.Keys) == 0 {
+		return fmt.Errorf("keyset pagination must have at least one key")
+	}
+	if k.Limit == 0 {
+		return fmt.Errorf("keyset pagination must have a limit")
+	}
+	return nil
+}
+
+// keysetPredicate returns a predicate matching the rows sorted after values.
+func keysetPredicate(keys []SortKey, values []interface{}) StatementBuilder {
+	if rowComparable(keys, values) {
+		columns := make([]string, len(keys))
+		params := make([]string, len(keys))
+		for i, key := range keys {
+			columns[i] = key.Column
+			params[i] = key.placeholder()
+		}
+
+		opr := ">"
+		if keys[0].Desc {
+			opr = "<"
+		}
+
+		if len(keys) == 1 {
+			return Expr(fmt.Sprintf("%s %s %s", columns[0], opr, params[0]), values[0])
+		}
+		return Expr(fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), opr, strings.Join(params, ",")), values...)
+	}
+
+	var or Or
+	for i, key := range keys {
+		after := key.after(values[i])
+		if after == nil {
+			continue
+		}
+
+		if i == 0 {
+			or = append(or, after)
+			continue
+		}
+
+		var and And
+		for j := 0; j < i; j++ {
+			and = append(and, keys[j].equal(values[j]))
+		}
+		or = append(or, append(and, after))
+	}
+
+	if len(or) == 0 {
+		return Expr("false")
+	}
+	return or
+}
+
+// rowComparable returns whether the keys can be compared with a single row
+// value comparison, which is the case when they all have the same direction
+// and no NULL values.
+func rowComparable(keys []SortKey, values []interface{}) bool {
+	for i, key := range keys {
+		if key.Nullable || values[i] == nil || key.Desc != keys[0].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+// Page trims the extra row selected by Select from rows, which must be a
+// pointer to a slice of structs, struct pointers or maps, restores the sort
+// order of previous pages and returns the cursors of the adjacent pages.
+func (k Keyset) Page(cursor string, rows interface{}) (Page, error) {
+	var page Page
+
+	if err := k.validate(); err != nil {
+		return page, err
+	}
+
+	c, err := k.decode(cursor)
+	if err != nil {
+		return page, err
+	}
+
+	rowsVal := reflect.ValueOf(rows)
+	if rowsVal.Kind() != reflect.Ptr || rowsVal.Elem().Kind() != reflect.Slice {
+		return page, fmt.Errorf("expected pointer to slice of rows, not %T", rows)
+	}
+	sliceVal := rowsVal.Elem()
+
+	more := uint64(sliceVal.Len()) > k.Limit
+	if more {
+		sliceVal.Set(sliceVal.Slice(0, int(k.Limit)))
+	}
+
+	n := sliceVal.Len()
+	if c.Before {
+		swap := reflect.Swapper(sliceVal.Interface())
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+
+	if n == 0 {
+		return page, nil
+	}
+
+	if !c.Before && more || c.Before {
+		page.Next, err = k.encode(sliceVal.Index(n-1), false)
+		if err != nil {
+			return page, err
+		}
+	}
+
+	if c.Before && more || !c.Before && c.Values != nil {
+		page.Prev, err = k.encode(sliceVal.Index(0), true)
+		if err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+func (k Keyset) encode(row reflect.Value, before bool) (string, error) {
+	for row.Kind() == reflect.Interface || row.Kind() == reflect.Ptr {
+		row = row.Elem()
+	}
+
+	fields := make(map[string]interface{})
+	switch row.Kind() {
+	case reflect.Map:
+		m, ok := row.Interface().(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("expected map[string]interface{} row, not %s", row.Type())
+		}
+		fields = m
+	case reflect.Struct:
+		columns, values := structValues(row, nil, nil)
+		for i, column := range columns {
+			fields[column] = values[i]
+		}
+	default:
+		return "", fmt.Errorf("expected struct or map row, not %s", row.Kind())
+	}
+
+	var err error
+	c := keysetCursor{Before: before, Values: make([]interface{}, len(k.Keys))}
+	for i, key := range k.Keys {
+		value, ok := fields[key.field()]
+		if !ok {
+			return "", fmt.Errorf("row has no field for sort key %s", key.field())
+		}
+		c.Values[i], err = cursorValue(value)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+var byteType = reflect.TypeOf(byte(0))
+
+// cursorValue converts a key value to one whose JSON encoding is the value's
+// text representation, which the key's Type cast parses. Valuers are replaced
+// by their driver value and 16 byte arrays are formatted as UUIDs.
+func cursorValue(value interface{}) (interface{}, error) {
+	if _, ok := value.(driver.Valuer); ok {
+		return driver.DefaultParameterConverter.ConvertValue(value)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Array && v.Type().Elem() == byteType && v.Len() == 16 {
+		b := make([]byte, 16)
+		reflect.Copy(reflect.ValueOf(b), v)
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+	}
+
+	return value, nil
+}
+
+func (k Keyset) decode(cursor string) (keysetCursor, error) {
+	var c keysetCursor
+	if cursor == "" {
+		return c, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	d := json.NewDecoder(strings.NewReader(string(data)))
+	d.UseNumber()
+	if err := d.Decode(&c); err != nil || len(c.Values) != len(k.Keys) {
+		return c, ErrInvalidCursor
+	}
+
+	for i, v := range c.Values {
+		if n, ok := v.(json.Number); ok {
+			if c.Values[i], err = n.Int64(); err != nil {
+				if c.Values[i], err = n.Float64(); err != nil {
+					return c, ErrInvalidCursor
+				}
+			}
+		}
+	}
+
+	return c, nil
+}
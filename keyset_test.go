@@ -0,0 +1,175 @@
+package sq
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keysetRow struct {
+	ID    int64
+	Score *int64
+}
+
+func TestKeysetSelect(t *testing.T) {
+	ks := Keyset{Keys: []SortKey{{Column: "p.created_at", Desc: true}, {Column: "p.id", Desc: true}}, Limit: 10}
+
+	qb, err := ks.Select(Select("id").From("posts p"), "")
+	require.NoError(t, err)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM posts p ORDER BY p.created_at DESC, p.id DESC LIMIT 11", sql)
+	assert.Empty(t, args)
+
+	rows := []map[string]interface{}{
+		{"created_at": "2020-01-02", "id": 2},
+		{"created_at": "2020-01-01", "id": 1},
+	}
+	cursor, err := ks.encode(reflect.ValueOf(rows[1]), false)
+	require.NoError(t, err)
+
+	qb, err = ks.Select(Select("id").From("posts p"), cursor)
+	require.NoError(t, err)
+	sql, args, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM posts p WHERE (p.created_at, p.id) < (?,?) ORDER BY p.created_at DESC, p.id DESC LIMIT 11", sql)
+	assert.Equal(t, []interface{}{"2020-01-01", int64(1)}, args)
+}
+
+func TestKeysetSelectMixed(t *testing.T) {
+	ks := Keyset{Keys: []SortKey{{Column: "score", Nullable: true}, {Column: "id", Desc: true}}, Limit: 2}
+
+	cursor, err := ks.encode(reflect.ValueOf(keysetRow{ID: 5, Score: int64Ptr(3)}), false)
+	require.NoError(t, err)
+	qb, err := ks.Select(Select("id").From("t"), cursor)
+	require.NoError(t, err)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE ((score > ? OR score IS NULL) OR (score = ? AND id < ?)) "+
+		"ORDER BY score NULLS LAST, id DESC LIMIT 3", sql)
+	assert.Equal(t, []interface{}{int64(3), int64(3), int64(5)}, args)
+
+	cursor, err = ks.encode(reflect.ValueOf(keysetRow{ID: 5}), false)
+	require.NoError(t, err)
+	qb, err = ks.Select(Select("id").From("t"), cursor)
+	require.NoError(t, err)
+	sql, args, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE ((score IS NULL AND id < ?)) ORDER BY score NULLS LAST, id DESC LIMIT 3", sql)
+	assert.Equal(t, []interface{}{int64(5)}, args)
+
+	cursor, err = ks.encode(reflect.ValueOf(keysetRow{ID: 5}), true)
+	require.NoError(t, err)
+	qb, err = ks.Select(Select("id").From("t"), cursor)
+	require.NoError(t, err)
+	sql, args, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE (score IS NOT NULL OR (score IS NULL AND id > ?)) "+
+		"ORDER BY score DESC NULLS FIRST, id LIMIT 3", sql)
+	assert.Equal(t, []interface{}{int64(5)}, args)
+}
+
+type keysetTypedRow struct {
+	CreatedAt time.Time
+	ID        [16]byte
+}
+
+func TestKeysetSelectTyped(t *testing.T) {
+	ks := Keyset{Keys: []SortKey{
+		{Column: "created_at", Type: "timestamptz", Desc: true},
+		{Column: "id", Type: "uuid", Desc: true},
+	}, Limit: 10}
+
+	row := keysetTypedRow{
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC),
+		ID:        [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+	}
+	cursor, err := ks.encode(reflect.ValueOf(row), false)
+	require.NoError(t, err)
+
+	qb, err := ks.Select(Select("id").From("t"), cursor)
+	require.NoError(t, err)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE (created_at, id) < (?::timestamptz,?::uuid) "+
+		"ORDER BY created_at DESC, id DESC LIMIT 11", sql)
+	assert.Equal(t, []interface{}{"2020-01-02T03:04:05.000006Z", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}, args)
+
+	ks.Keys[0].Nullable = true
+	qb, err = ks.Select(Select("id").From("t"), cursor)
+	require.NoError(t, err)
+	sql, _, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE ((created_at < ?::timestamptz OR created_at IS NULL) OR "+
+		"(created_at = ?::timestamptz AND id < ?::uuid)) ORDER BY created_at DESC NULLS LAST, id DESC LIMIT 11", sql)
+}
+
+func TestKeysetPage(t *testing.T) {
+	ks := Keyset{Keys: []SortKey{{Column: "id"}}, Limit: 2}
+
+	rows := []keysetRow{{ID: 1}, {ID: 2}, {ID: 3}}
+	page, err := ks.Page("", &rows)
+	require.NoError(t, err)
+	assert.Equal(t, []keysetRow{{ID: 1}, {ID: 2}}, rows)
+	assert.Empty(t, page.Prev)
+	require.NotEmpty(t, page.Next)
+
+	qb, err := ks.Select(Select("id").From("t"), page.Next)
+	require.NoError(t, err)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE id > ? ORDER BY id LIMIT 3", sql)
+	assert.Equal(t, []interface{}{int64(2)}, args)
+
+	rows = []keysetRow{{ID: 3}}
+	page2, err := ks.Page(page.Next, &rows)
+	require.NoError(t, err)
+	assert.Empty(t, page2.Next)
+	require.NotEmpty(t, page2.Prev)
+
+	qb, err = ks.Select(Select("id").From("t"), page2.Prev)
+	require.NoError(t, err)
+	sql, args, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE id < ? ORDER BY id DESC LIMIT 3", sql)
+	assert.Equal(t, []interface{}{int64(3)}, args)
+
+	rows = []keysetRow{{ID: 2}, {ID: 1}}
+	page3, err := ks.Page(page2.Prev, &rows)
+	require.NoError(t, err)
+	assert.Equal(t, []keysetRow{{ID: 1}, {ID: 2}}, rows)
+	assert.Empty(t, page3.Prev)
+	assert.NotEmpty(t, page3.Next)
+}
+
+func TestKeysetErrors(t *testing.T) {
+	ks := Keyset{Keys: []SortKey{{Column: "id"}}, Limit: 2}
+
+	_, err := ks.Select(Select("id").From("t"), "not a cursor")
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	_, err = ks.Select(Select("id").From("t").OrderBy("id"), "")
+	assert.Error(t, err)
+
+	_, err = ks.Select(Select("id").From("t").Offset(10), "")
+	assert.Error(t, err)
+
+	_, err = Keyset{}.Select(Select("id").From("t"), "")
+	assert.Error(t, err)
+
+	_, err = Keyset{Keys: ks.Keys}.Select(Select("id").From("t"), "")
+	assert.EqualError(t, err, "keyset pagination must have a limit")
+
+	_, err = Keyset{Keys: ks.Keys}.Page("", &[]keysetRow{})
+	assert.EqualError(t, err, "keyset pagination must have a limit")
+
+	_, err = ks.Page("", []keysetRow{})
+	assert.Error(t, err)
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,168 @@
+package sq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// Notification is a notification received by a Subscription.
+type Notification = pgconn.Notification
+
+// Subscription is a subscription to notifications on one or more channels,
+// see Pool.Listen.
+type Subscription interface {
+	// Notifications returns the channel on which notifications are delivered,
+	// which is closed when the subscription ends.
+	Notifications() <-chan *Notification
+
+	// Run calls fn for each notification until the subscription ends, ctx is
+	// done or fn returns an error, which is returned.
+	Run(ctx context.Context, fn func(n *Notification) error) error
+
+	// Close ends the subscription and releases its connection.
+	Close()
+}
+
+var (
+	// listenMinRetryDelay and listenMaxRetryDelay bound the delay between
+	// attempts to reconnect a Subscription.
+	listenMinRetryDelay = 100 * time.Millisecond
+	listenMaxRetryDelay = 30 * time.Second
+)
+
+// pgxSubscription is a Subscription holding a dedicated connection, which is
+// reconnected when lost.
+type pgxSubscription struct {
+	config        *pgx.ConnConfig
+	channels      []string
+	notifications chan *Notification
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+func (p *pgxPool) Listen(ctx context.Context, channels ...string) (Subscription, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("listen requires at least one channel")
+	}
+
+	s := &pgxSubscription{
+		config:        p.pool.Config().ConnConfig,
+		channels:      channels,
+		notifications: make(chan *Notification),
+		done:          make(chan struct{}),
+	}
+
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx, conn)
+
+	return s, nil
+}
+
+// connect returns a new connection listening on the channels of s.
+func (s *pgxSubscription) connect(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range s.channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			_ = conn.Close(ctx)
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// run delivers notifications until ctx is done, reconnecting with exponential
+// backoff whenever the connection is lost. Notifications sent while
+// reconnecting are lost.
+func (s *pgxSubscription) run(ctx context.Context, conn *pgx.Conn) {
+	defer close(s.done)
+	defer close(s.notifications)
+
+	delay := listenMinRetryDelay
+	for {
+		if conn != nil {
+			s.receive(ctx, conn)
+			_ = conn.Close(context.Background())
+			conn = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		var err error
+		if conn, err = s.connect(ctx); err != nil {
+			if delay *= 2; delay > listenMaxRetryDelay {
+				delay = listenMaxRetryDelay
+			}
+			continue
+		}
+		delay = listenMinRetryDelay
+	}
+}
+
+// receive delivers the notifications of conn until it fails or ctx is done.
+func (s *pgxSubscription) receive(ctx context.Context, conn *pgx.Conn) {
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return
+		}
+
+		select {
+		case s.notifications <- n:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *pgxSubscription) Notifications() <-chan *Notification {
+	return s.notifications
+}
+
+func (s *pgxSubscription) Run(ctx context.Context, fn func(n *Notification) error) error {
+	return runSubscription(ctx, s.notifications, fn)
+}
+
+func (s *pgxSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// runSubscription calls fn for each notification received on notifications.
+func runSubscription(ctx context.Context, notifications <-chan *Notification, fn func(n *Notification) error) error {
+	for {
+		select {
+		case n, ok := <-notifications:
+			if !ok {
+				return nil
+			}
+			if err := fn(n); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func notify(ctx context.Context, e Executor, channel, payload string) error {
+	_, err := e.Exec(ctx, Expr("SELECT pg_notify(?, ?)", channel, payload))
+	return err
+}
@@ -0,0 +1,37 @@
+package sq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSubscription(t *testing.T) {
+	notifications := make(chan *Notification, 3)
+	notifications <- &Notification{Channel: "a", Payload: "1"}
+	notifications <- &Notification{Channel: "a", Payload: "2"}
+	notifications <- &Notification{Channel: "a", Payload: "3"}
+
+	var payloads []string
+	errStop := errors.New("stop")
+	err := runSubscription(context.Background(), notifications, func(n *Notification) error {
+		payloads = append(payloads, n.Payload)
+		if n.Payload == "2" {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, []string{"1", "2"}, payloads)
+
+	close(notifications)
+	err = runSubscription(context.Background(), notifications, func(n *Notification) error { return nil })
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = runSubscription(ctx, make(chan *Notification), func(n *Notification) error { return nil })
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,75 @@
+package sq
+
+import "fmt"
+
+// OrderBuilder builds an ORDER BY expression for use with OrderByClause.
+type OrderBuilder interface {
+	// NullsFirst sorts NULL values before non-NULL values.
+	NullsFirst() OrderBuilder
+
+	// NullsLast sorts NULL values after non-NULL values.
+	NullsLast() OrderBuilder
+
+	ToSQL() (sqlStr string, args []interface{}, err error)
+}
+
+type orderBuilder struct {
+	expr  StatementBuilder
+	dir   string
+	nulls string
+}
+
+// Asc returns an ascending OrderBuilder for the given expression.
+//
+//     .OrderByClause(Asc("array_position(?, id)", ids))
+func Asc(expr interface{}, args ...interface{}) OrderBuilder {
+	return orderBuilder{expr: newPart(expr, args...), dir: "ASC"}
+}
+
+// Desc returns a descending OrderBuilder for the given expression.
+//
+//     .OrderByClause(Desc("created_at").NullsLast())
+func Desc(expr interface{}, args ...interface{}) OrderBuilder {
+	return orderBuilder{expr: newPart(expr, args...), dir: "DESC"}
+}
+
+// NullsFirst returns an OrderBuilder for the given expression in the default
+// direction with NULL values first.
+func NullsFirst(expr interface{}, args ...interface{}) OrderBuilder {
+	return orderBuilder{expr: newPart(expr, args...)}.NullsFirst()
+}
+
+// NullsLast returns an OrderBuilder for the given expression in the default
+// direction with NULL values last.
+func NullsLast(expr interface{}, args ...interface{}) OrderBuilder {
+	return orderBuilder{expr: newPart(expr, args...)}.NullsLast()
+}
+
+func (o orderBuilder) NullsFirst() OrderBuilder {
+	o.nulls = "NULLS FIRST"
+	return o
+}
+
+func (o orderBuilder) NullsLast() OrderBuilder {
+	o.nulls = "NULLS LAST"
+	return o
+}
+
+func (o orderBuilder) ToSQL() (sql string, args []interface{}, err error) {
+	sql, args, err = o.expr.ToSQL()
+	if err != nil {
+		return
+	}
+	if sql == "" {
+		err = fmt.Errorf("order by expression must not be empty")
+		return
+	}
+
+	if o.dir != "" {
+		sql += " " + o.dir
+	}
+	if o.nulls != "" {
+		sql += " " + o.nulls
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBuilderToSQL(t *testing.T) {
+	ids := []int{3, 1, 2}
+	qb := Select("a").
+		From("b").
+		OrderByClause(Asc("array_position(?, id)", ids)).
+		OrderByClause(Desc("c").NullsLast()).
+		OrderByClause(NullsFirst(Case("d").When("1", "?").Else("e"))).
+		OrderByClause(NullsLast("point(?, ?) <-> loc", 1.5, 2.5))
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT a FROM b ORDER BY " +
+		"array_position(?, id) ASC, " +
+		"c DESC NULLS LAST, " +
+		"CASE d WHEN 1 THEN ? ELSE e END NULLS FIRST, " +
+		"point(?, ?) <-> loc NULLS LAST"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{ids, 1.5, 2.5}, args)
+}
+
+func TestOrderBuilderToSQLErr(t *testing.T) {
+	_, _, err := Desc("").ToSQL()
+	assert.Error(t, err)
+}
+
+func TestGroupByClause(t *testing.T) {
+	qb := Select("count(*)").
+		From("b").
+		GroupBy("c").
+		GroupByClause("date_trunc(?, created_at)", "day").
+		Where("d = ?", 1).
+		Having("count(*) > ?", 2)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM b WHERE d = ? GROUP BY c, date_trunc(?, created_at) HAVING count(*) > ?", sql)
+	assert.Equal(t, []interface{}{1, "day", 2}, args)
+}
+
+func TestUpdateDeleteOrderByClause(t *testing.T) {
+	sql, args, err := Update("a").Set("b", 1).OrderByClause(Desc("c <-> ?", 2)).Limit(5).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = ? WHERE ctid IN (SELECT ctid FROM a ORDER BY c <-> ? DESC LIMIT 5)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = Delete("a").Where("b = ?", 1).OrderBy("d").OrderByClause(Asc("c <-> ?", 2)).Offset(5).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE ctid IN (SELECT ctid FROM a WHERE b = ? ORDER BY d, c <-> ? ASC OFFSET 5)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	_, _, err = Update("a").Set("b", 1).OrderBy("c").ToSQL()
+	assert.EqualError(t, err, "update statements with ORDER BY must also have LIMIT or OFFSET")
+
+	_, _, err = Delete("a").OrderBy("c").ToSQL()
+	assert.EqualError(t, err, "delete statements with ORDER BY must also have LIMIT or OFFSET")
+}
@@ -3,6 +3,7 @@ package sq
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
 type part struct {
@@ -21,8 +22,7 @@ func (p part) ToSQL() (sql string, args []interface{}, err error) {
 	case StatementBuilder:
 		sql, args, err = pred.ToSQL()
 	case string:
-		sql = pred
-		args = p.args
+		sql, args, err = Expr(pred, p.args...).ToSQL()
 	default:
 		err = fmt.Errorf("expected string or StatementBuilder, not %T", pred)
 	}
@@ -53,3 +53,68 @@ func appendToSQL(parts []StatementBuilder, w io.Writer, sep string, args []inter
 	}
 	return args, nil
 }
+
+// topLevel calls fn with the offset of each byte of sql outside parentheses,
+// brackets and quoted strings or identifiers, until fn returns false.
+func topLevel(sql string, fn func(i int) bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"':
+			quote = c
+			continue
+		case c == '(' || c == '[':
+			depth++
+			continue
+		case c == ')' || c == ']':
+			depth--
+			continue
+		}
+
+		if depth == 0 && !fn(i) {
+			return
+		}
+	}
+}
+
+// topLevelKeyword returns the offsets of the keyword in sql outside
+// parentheses and quotes, matched case-insensitively as a whole word.
+func topLevelKeyword(sql, keyword string) []int {
+	var offsets []int
+	topLevel(sql, func(i int) bool {
+		end := i + len(keyword)
+		if end <= len(sql) && strings.EqualFold(sql[i:end], keyword) &&
+			(i == 0 || !isWordByte(sql[i-1])) && (end == len(sql) || !isWordByte(sql[end])) {
+			offsets = append(offsets, i)
+		}
+		return true
+	})
+	return offsets
+}
+
+// isWordByte reports whether c may be part of an identifier or keyword.
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// splitTopLevel splits sql at the commas outside parentheses and quotes.
+func splitTopLevel(sql string) []string {
+	var parts []string
+	start := 0
+	topLevel(sql, func(i int) bool {
+		if sql[i] == ',' {
+			parts = append(parts, strings.TrimSpace(sql[start:i]))
+			start = i + 1
+		}
+		return true
+	})
+	return append(parts, strings.TrimSpace(sql[start:]))
+}
@@ -0,0 +1,135 @@
+package sq
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// scanner is the common interface of the rows of pgx and database/sql.
+type scanner interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+func scalar(ctx context.Context, e pgxExecutor, qb StatementBuilder, dst interface{}) error {
+	rows, err := query(ctx, e, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanScalar(rows, len(rows.FieldDescriptions()), dst)
+}
+
+func column(ctx context.Context, e pgxExecutor, qb StatementBuilder, dst interface{}) error {
+	rows, err := query(ctx, e, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanColumn(rows, len(rows.FieldDescriptions()), dst)
+}
+
+func mapRows(ctx context.Context, e pgxExecutor, qb StatementBuilder, dst interface{}) error {
+	rows, err := query(ctx, e, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanMap(rows, len(rows.FieldDescriptions()), dst)
+}
+
+func scanScalar(rows scanner, columns int, dst interface{}) error {
+	if err := checkColumns(columns, 1); err != nil {
+		return err
+	}
+
+	found, err := scanOne(rows, func() error { return rows.Scan(dst) })
+	if err == nil && !found {
+		err = ErrNoRows
+	}
+	return err
+}
+
+// scanOne calls scan for the first of rows, returning ErrTooManyRows if there
+// is more than one row.
+func scanOne(rows scanner, scan func() error) (bool, error) {
+	n := 0
+	for rows.Next() {
+		n++
+		if n == 1 {
+			if err := scan(); err != nil {
+				return false, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	if n > 1 {
+		return false, fmt.Errorf("%w: expected 1 row, got: %d", ErrTooManyRows, n)
+	}
+	return n == 1, nil
+}
+
+func scanColumn(rows scanner, columns int, dst interface{}) error {
+	dstVal := reflect.ValueOf(dst)
+	if dstVal.Kind() != reflect.Ptr || dstVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected pointer to slice, not %T", dst)
+	}
+	sliceVal := dstVal.Elem()
+	elemType := sliceVal.Type().Elem()
+
+	if err := checkColumns(columns, 1); err != nil {
+		return err
+	}
+
+	sliceVal.Set(reflect.MakeSlice(sliceVal.Type(), 0, 0))
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := rows.Scan(elem.Interface()); err != nil {
+			return err
+		}
+		sliceVal.Set(reflect.Append(sliceVal, elem.Elem()))
+	}
+
+	return rows.Err()
+}
+
+func scanMap(rows scanner, columns int, dst interface{}) error {
+	dstVal := reflect.ValueOf(dst)
+	if dstVal.Kind() != reflect.Ptr || dstVal.Elem().Kind() != reflect.Map {
+		return fmt.Errorf("expected pointer to map, not %T", dst)
+	}
+	mapVal := dstVal.Elem()
+	keyType := mapVal.Type().Key()
+	elemType := mapVal.Type().Elem()
+
+	if err := checkColumns(columns, 2); err != nil {
+		return err
+	}
+
+	mapVal.Set(reflect.MakeMap(mapVal.Type()))
+	for rows.Next() {
+		key := reflect.New(keyType)
+		elem := reflect.New(elemType)
+		if err := rows.Scan(key.Interface(), elem.Interface()); err != nil {
+			return err
+		}
+		mapVal.SetMapIndex(key.Elem(), elem.Elem())
+	}
+
+	return rows.Err()
+}
+
+func checkColumns(got, n int) error {
+	if got != n {
+		return fmt.Errorf("expected %d result columns, got: %d", n, got)
+	}
+	return nil
+}
@@ -3,11 +3,17 @@ package sq
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"reflect"
+	"regexp"
 	"strconv"
-	"strings"
 )
 
 // SelectBuilder builds SQL SELECT statements.
+//
+// SelectBuilder is immutable: each method returns a new builder and leaves the
+// receiver unchanged, so a base query can be safely shared and extended from
+// multiple goroutines.
 type SelectBuilder interface {
 	// Prefix adds an expression to the beginning of the query.
 	Prefix(sql string, args ...interface{}) SelectBuilder
@@ -15,6 +21,15 @@ type SelectBuilder interface {
 	// Distinct adds a DISTINCT clause to the query.
 	Distinct() SelectBuilder
 
+	// DistinctOn adds a DISTINCT ON clause to the query. Each expression may be
+	// a string or a StatementBuilder.
+	//
+	// ToSQL returns an error if the ORDER BY clause does not start with the
+	// DISTINCT ON expressions, as required by PostgreSQL.
+	//
+	//   DistinctOn("user_id").OrderBy("user_id", "created_at DESC")
+	DistinctOn(exprs ...interface{}) SelectBuilder
+
 	// Columns adds result columns to the query.
 	Columns(columns ...string) SelectBuilder
 
@@ -28,6 +43,15 @@ type SelectBuilder interface {
 	// From sets the FROM clause of the query.
 	From(from string) SelectBuilder
 
+	// FromClause sets the FROM clause of the query to an expression such as a
+	// subquery or a VALUES list.
+	// Unlike From, FromClause accepts a StatementBuilder or args which will be
+	// bound to placeholders in the expression string.
+	//
+	//   FromClause(Values([]interface{}{1, "a"}).As("v", "id", "name"))
+	//   FromClause("generate_series(1, ?) AS n", 10)
+	FromClause(pred interface{}, args ...interface{}) SelectBuilder
+
 	// JoinClause adds a join clause to the query.
 	JoinClause(join string, args ...interface{}) SelectBuilder
 
@@ -65,6 +89,31 @@ type SelectBuilder interface {
 	// GroupBy adds GROUP BY expressions to the query.
 	GroupBy(groupBys ...string) SelectBuilder
 
+	// GroupByClause adds a GROUP BY expression to the query.
+	//
+	// See OrderByClause.
+	GroupByClause(pred interface{}, args ...interface{}) SelectBuilder
+
+	// GroupByRollup adds a ROLLUP grouping element to the GROUP BY clause of the
+	// query. Each expression may be a string, a StatementBuilder or a slice of
+	// those, which is rendered as a composite column.
+	//
+	//   GroupByRollup("region", []string{"country", "city"}) == "GROUP BY ROLLUP (region, (country, city))"
+	GroupByRollup(exprs ...interface{}) SelectBuilder
+
+	// GroupByCube adds a CUBE grouping element to the GROUP BY clause of the
+	// query.
+	//
+	// See GroupByRollup.
+	GroupByCube(exprs ...interface{}) SelectBuilder
+
+	// GroupingSets adds a GROUPING SETS grouping element to the GROUP BY clause
+	// of the query. Each set may be a grouping element (see GroupByRollup) or
+	// nil for the empty grouping set.
+	//
+	//   GroupingSets([]string{"a", "b"}, []string{"a"}, nil) == "GROUP BY GROUPING SETS ((a, b), (a), ())"
+	GroupingSets(sets ...interface{}) SelectBuilder
+
 	// Having adds an expression to the HAVING clause of the query.
 	//
 	// See Where.
@@ -73,12 +122,49 @@ type SelectBuilder interface {
 	// OrderBy adds ORDER BY expressions to the query.
 	OrderBy(orderBys ...string) SelectBuilder
 
+	// OrderByClause adds an ORDER BY expression to the query.
+	// Unlike OrderBy, OrderByClause accepts a StatementBuilder or args which will
+	// be bound to placeholders in the expression string.
+	//
+	//   OrderByClause("array_position(?, id)", ids)
+	//   OrderByClause(Desc(TSRank("body", "english", query)))
+	OrderByClause(pred interface{}, args ...interface{}) SelectBuilder
+
 	// Limit sets a LIMIT clause on the query.
 	Limit(limit uint64) SelectBuilder
 
+	// LimitClause sets a LIMIT clause on the query to an expression.
+	// Unlike Limit, LimitClause accepts a StatementBuilder, which is wrapped in
+	// parentheses, or args which will be bound to placeholders in the
+	// expression string.
+	//
+	//   LimitClause(Select("page_size").From("settings"))
+	LimitClause(pred interface{}, args ...interface{}) SelectBuilder
+
 	// Offset sets a OFFSET clause on the query.
 	Offset(offset uint64) SelectBuilder
 
+	// OffsetClause sets a OFFSET clause on the query to an expression.
+	//
+	// See LimitClause.
+	OffsetClause(pred interface{}, args ...interface{}) SelectBuilder
+
+	// FetchFirst sets a FETCH FIRST n ROWS ONLY clause on the query, the SQL
+	// standard equivalent of Limit. It cannot be combined with Limit.
+	FetchFirst(count uint64) SelectBuilder
+
+	// FetchFirstWithTies sets a FETCH FIRST n ROWS WITH TIES clause on the query,
+	// which also returns any rows that tie for the last place in the ORDER BY
+	// clause. It cannot be combined with Limit and requires OrderBy.
+	FetchFirstWithTies(count uint64) SelectBuilder
+
+	// BindLimitOffset binds the values of Limit, Offset and FetchFirst to
+	// placeholders rather than formatting them into the SQL, so queries that
+	// only differ by page share a prepared statement.
+	//
+	// See also the BindLimitOffset variable.
+	BindLimitOffset() SelectBuilder
+
 	// Suffix adds an expression to the end of the query.
 	Suffix(sql string, args ...interface{}) SelectBuilder
 
@@ -94,27 +180,61 @@ type SelectBuilder interface {
 type selectBuilder struct {
 	prefixes    exprs
 	distinct    bool
+	distinctOn  []StatementBuilder
 	columns     []StatementBuilder
-	from        string
+	from        StatementBuilder
 	joins       exprs
 	whereParts  []StatementBuilder
-	groupBys    []string
+	groupBys    []StatementBuilder
 	havingParts []StatementBuilder
-	orderBys    []string
+	orderBys    []StatementBuilder
 
-	limit       uint64
-	limitValid  bool
-	offset      uint64
-	offsetValid bool
+	limit           StatementBuilder
+	offset          StatementBuilder
+	fetch           StatementBuilder
+	fetchWithTies   bool
+	bindLimitOffset bool
 
 	suffixes exprs
 }
 
+// BindLimitOffset sets whether the values of Limit, Offset and FetchFirst are
+// bound to placeholders by default for all builders, including the emulated
+// LIMIT and OFFSET of UpdateBuilder and DeleteBuilder. It should only be set
+// during initialization.
+//
+// See SelectBuilder.BindLimitOffset.
+var BindLimitOffset = false
+
+// rowCount is a row count for a LIMIT, OFFSET or FETCH FIRST clause, which is
+// either bound to a placeholder or formatted into the SQL when rendered.
+type rowCount uint64
+
+func (c rowCount) ToSQL() (string, []interface{}, error) {
+	return strconv.FormatUint(uint64(c), 10), nil, nil
+}
+
 // NewSelectBuilder creates new instance of SelectBuilder
 func NewSelectBuilder() SelectBuilder {
 	return &selectBuilder{}
 }
 
+// clone returns a shallow copy of b whose slices are capped so that appends
+// made by the copy never write into the backing arrays shared with b.
+func (b *selectBuilder) clone() *selectBuilder {
+	nb := *b
+	nb.prefixes = nb.prefixes[:len(nb.prefixes):len(nb.prefixes)]
+	nb.distinctOn = nb.distinctOn[:len(nb.distinctOn):len(nb.distinctOn)]
+	nb.columns = nb.columns[:len(nb.columns):len(nb.columns)]
+	nb.joins = nb.joins[:len(nb.joins):len(nb.joins)]
+	nb.whereParts = nb.whereParts[:len(nb.whereParts):len(nb.whereParts)]
+	nb.groupBys = nb.groupBys[:len(nb.groupBys):len(nb.groupBys)]
+	nb.havingParts = nb.havingParts[:len(nb.havingParts):len(nb.havingParts)]
+	nb.orderBys = nb.orderBys[:len(nb.orderBys):len(nb.orderBys)]
+	nb.suffixes = nb.suffixes[:len(nb.suffixes):len(nb.suffixes)]
+	return &nb
+}
+
 func (b *selectBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	if len(b.columns) == 0 {
 		err = fmt.Errorf("select statements must have at least one result column")
@@ -133,7 +253,19 @@ func (b *selectBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 	sql.WriteString("SELECT ")
 
-	if b.distinct {
+	if len(b.distinctOn) > 0 {
+		err = b.validateDistinctOn()
+		if err != nil {
+			return
+		}
+
+		sql.WriteString("DISTINCT ON (")
+		args, err = appendToSQL(b.distinctOn, sql, ", ", args)
+		if err != nil {
+			return
+		}
+		sql.WriteString(") ")
+	} else if b.distinct {
 		sql.WriteString("DISTINCT ")
 	}
 
@@ -144,9 +276,19 @@ func (b *selectBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 		}
 	}
 
-	if len(b.from) > 0 {
-		sql.WriteString(" FROM ")
-		sql.WriteString(b.from)
+	if b.from != nil {
+		var fromSQL string
+		var fromArgs []interface{}
+		fromSQL, fromArgs, err = b.from.ToSQL()
+		if err != nil {
+			return
+		}
+
+		if len(fromSQL) > 0 {
+			sql.WriteString(" FROM ")
+			sql.WriteString(fromSQL)
+			args = append(args, fromArgs...)
+		}
 	}
 
 	if len(b.joins) > 0 {
@@ -167,7 +309,10 @@ func (b *selectBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.groupBys) > 0 {
 		sql.WriteString(" GROUP BY ")
-		sql.WriteString(strings.Join(b.groupBys, ", "))
+		args, err = appendToSQL(b.groupBys, sql, ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.havingParts) > 0 {
@@ -180,18 +325,50 @@ func (b *selectBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.orderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		args, err = appendToSQL(b.orderBys, sql, ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	// TODO: limit == 0 and offset == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
+	if b.fetch != nil {
+		if b.limit != nil {
+			err = fmt.Errorf("select statements cannot have both LIMIT and FETCH FIRST")
+			return
+		}
+		if b.fetchWithTies && len(b.orderBys) == 0 {
+			err = fmt.Errorf("select statements with FETCH FIRST WITH TIES must have ORDER BY")
+			return
+		}
+	}
+
+	if b.limit != nil {
 		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+		args, err = b.appendCount(sql, b.limit, args)
+		if err != nil {
+			return
+		}
 	}
 
-	if b.offsetValid {
+	if b.offset != nil {
 		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+		args, err = b.appendCount(sql, b.offset, args)
+		if err != nil {
+			return
+		}
+	}
+
+	if b.fetch != nil {
+		sql.WriteString(" FETCH FIRST ")
+		args, err = b.appendCount(sql, b.fetch, args)
+		if err != nil {
+			return
+		}
+		if b.fetchWithTies {
+			sql.WriteString(" ROWS WITH TIES")
+		} else {
+			sql.WriteString(" ROWS ONLY")
+		}
 	}
 
 	if len(b.suffixes) > 0 {
@@ -207,18 +384,126 @@ func (b *selectBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 }
 
+// validateDistinctOn checks that the leading ORDER BY expressions are the
+// DISTINCT ON expressions, in any order.
+func (b *selectBuilder) validateDistinctOn() error {
+	if len(b.orderBys) == 0 {
+		return nil
+	}
+
+	distinctOn := make([]sqlWithArgs, len(b.distinctOn))
+	for i, p := range b.distinctOn {
+		sql, args, err := p.ToSQL()
+		if err != nil {
+			return err
+		}
+		distinctOn[i] = sqlWithArgs{sql: sql, args: args}
+	}
+
+	var orderBys []sqlWithArgs
+	for _, p := range b.orderBys {
+		exprs, err := orderByExprs(p)
+		if err != nil {
+			return err
+		}
+		orderBys = append(orderBys, exprs...)
+	}
+
+	matched := make([]bool, len(distinctOn))
+	remaining := len(distinctOn)
+	for _, o := range orderBys {
+		if remaining == 0 {
+			break
+		}
+
+		found := false
+		for i, d := range distinctOn {
+			if d.sql == o.sql && reflect.DeepEqual(d.args, o.args) {
+				if !matched[i] {
+					matched[i] = true
+					remaining--
+				}
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("DISTINCT ON expressions must match initial ORDER BY expressions, got %q", o.sql)
+		}
+	}
+
+	return nil
+}
+
+type sqlWithArgs struct {
+	sql  string
+	args []interface{}
+}
+
+var orderByDirection = regexp.MustCompile(`(?i)(\s+(ASC|DESC))?(\s+NULLS\s+(FIRST|LAST))?\s*$`)
+
+// orderByExprs returns the SQL of the expressions of an ORDER BY clause entry,
+// which may list several comma separated expressions, without their sort
+// directions.
+func orderByExprs(p StatementBuilder) ([]sqlWithArgs, error) {
+	if pt, ok := p.(*part); ok {
+		if o, ok := pt.pred.(orderBuilder); ok {
+			sql, args, err := o.expr.ToSQL()
+			return []sqlWithArgs{{sql: sql, args: args}}, err
+		}
+	}
+
+	sql, args, err := p.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var exprs []sqlWithArgs
+	for _, expr := range splitTopLevel(sql) {
+		n, err := countPlaceholders(expr)
+		if err != nil {
+			return nil, err
+		}
+		if n > len(args) {
+			n = len(args)
+		}
+
+		var exprArgs []interface{}
+		if n > 0 {
+			exprArgs = args[:n:n]
+			args = args[n:]
+		}
+
+		exprs = append(exprs, sqlWithArgs{
+			sql:  orderByDirection.ReplaceAllString(expr, ""),
+			args: exprArgs,
+		})
+	}
+	return exprs, nil
+}
+
 func (b *selectBuilder) Prefix(sql string, args ...interface{}) SelectBuilder {
+	b = b.clone()
 	b.prefixes = append(b.prefixes, expr{sql: sql, args: args})
 	return b
 }
 
 func (b *selectBuilder) Distinct() SelectBuilder {
+	b = b.clone()
 	b.distinct = true
 
 	return b
 }
 
+func (b *selectBuilder) DistinctOn(exprs ...interface{}) SelectBuilder {
+	b = b.clone()
+	for _, e := range exprs {
+		b.distinctOn = append(b.distinctOn, newPart(e))
+	}
+	return b
+}
+
 func (b *selectBuilder) Columns(columns ...string) SelectBuilder {
+	b = b.clone()
 	for _, str := range columns {
 		b.columns = append(b.columns, newPart(str))
 	}
@@ -227,17 +512,26 @@ func (b *selectBuilder) Columns(columns ...string) SelectBuilder {
 }
 
 func (b *selectBuilder) Column(column interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
 	b.columns = append(b.columns, newPart(column, args...))
 
 	return b
 }
 
 func (b *selectBuilder) From(from string) SelectBuilder {
-	b.from = from
+	b = b.clone()
+	b.from = newPart(from)
+	return b
+}
+
+func (b *selectBuilder) FromClause(pred interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
+	b.from = newPart(pred, args...)
 	return b
 }
 
 func (b *selectBuilder) JoinClause(join string, args ...interface{}) SelectBuilder {
+	b = b.clone()
 	b.joins = append(b.joins, expr{sql: join, args: args})
 
 	return b
@@ -256,38 +550,121 @@ func (b *selectBuilder) RightJoin(join string, args ...interface{}) SelectBuilde
 }
 
 func (b *selectBuilder) Where(pred interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
 	b.whereParts = append(b.whereParts, newWherePart(pred, args...))
 	return b
 }
 
 func (b *selectBuilder) GroupBy(groupBys ...string) SelectBuilder {
-	b.groupBys = append(b.groupBys, groupBys...)
+	b = b.clone()
+	for _, str := range groupBys {
+		b.groupBys = append(b.groupBys, newPart(str))
+	}
 	return b
 }
 
+func (b *selectBuilder) GroupByClause(pred interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
+	b.groupBys = append(b.groupBys, newPart(pred, args...))
+	return b
+}
+
+func (b *selectBuilder) GroupByRollup(exprs ...interface{}) SelectBuilder {
+	return b.GroupByClause(groupingExpr{fn: "ROLLUP", exprs: exprs})
+}
+
+func (b *selectBuilder) GroupByCube(exprs ...interface{}) SelectBuilder {
+	return b.GroupByClause(groupingExpr{fn: "CUBE", exprs: exprs})
+}
+
+func (b *selectBuilder) GroupingSets(sets ...interface{}) SelectBuilder {
+	return b.GroupByClause(groupingExpr{fn: "GROUPING SETS", exprs: sets, sets: true})
+}
+
 func (b *selectBuilder) Having(pred interface{}, rest ...interface{}) SelectBuilder {
+	b = b.clone()
 	b.havingParts = append(b.havingParts, newWherePart(pred, rest...))
 	return b
 }
 
 func (b *selectBuilder) OrderBy(orderBys ...string) SelectBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	b = b.clone()
+	for _, str := range orderBys {
+		b.orderBys = append(b.orderBys, newPart(str))
+	}
+	return b
+}
+
+func (b *selectBuilder) OrderByClause(pred interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
+	b.orderBys = append(b.orderBys, newPart(pred, args...))
 	return b
 }
 
+// appendCount writes the value of a LIMIT, OFFSET or FETCH FIRST clause.
+func (b *selectBuilder) appendCount(w io.Writer, p StatementBuilder, args []interface{}) ([]interface{}, error) {
+	if c, ok := p.(rowCount); ok && (b.bindLimitOffset || BindLimitOffset) {
+		_, err := io.WriteString(w, "?")
+		return append(args, uint64(c)), err
+	}
+	return appendToSQL([]StatementBuilder{p}, w, "", args)
+}
+
+// countPart returns the StatementBuilder for a LimitClause or OffsetClause
+// expression.
+func countPart(pred interface{}, args ...interface{}) StatementBuilder {
+	if sb, ok := pred.(StatementBuilder); ok {
+		return Expr("(?)", sb)
+	}
+	return newPart(pred, args...)
+}
+
 func (b *selectBuilder) Limit(limit uint64) SelectBuilder {
-	b.limit = limit
-	b.limitValid = true
+	b = b.clone()
+	b.limit = rowCount(limit)
+	return b
+}
+
+func (b *selectBuilder) LimitClause(pred interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
+	b.limit = countPart(pred, args...)
 	return b
 }
 
 func (b *selectBuilder) Offset(offset uint64) SelectBuilder {
-	b.offset = offset
-	b.offsetValid = true
+	b = b.clone()
+	b.offset = rowCount(offset)
+	return b
+}
+
+func (b *selectBuilder) OffsetClause(pred interface{}, args ...interface{}) SelectBuilder {
+	b = b.clone()
+	b.offset = countPart(pred, args...)
+	return b
+}
+
+func (b *selectBuilder) FetchFirst(n uint64) SelectBuilder {
+	b = b.clone()
+	b.fetch = rowCount(n)
+	b.fetchWithTies = false
+	return b
+}
+
+func (b *selectBuilder) FetchFirstWithTies(n uint64) SelectBuilder {
+	b = b.clone()
+	b.fetch = rowCount(n)
+	b.fetchWithTies = true
+	return b
+}
+
+func (b *selectBuilder) BindLimitOffset() SelectBuilder {
+	b = b.clone()
+	b.bindLimitOffset = true
 	return b
 }
 
 func (b *selectBuilder) Suffix(sql string, args ...interface{}) SelectBuilder {
+	b = b.clone()
 	b.suffixes = append(b.suffixes, expr{sql: sql, args: args})
 
 	return b
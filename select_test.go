@@ -1,6 +1,7 @@
 package sq
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -100,3 +101,153 @@ func TestSelectBuilderToSQLErr(t *testing.T) {
 	_, _, err := Select().From("x").ToSQL()
 	assert.Error(t, err)
 }
+
+func TestSelectBuilderImmutable(t *testing.T) {
+	base := Select("a").From("b").Where("c = ?", 1).Where("d = ?", 2).Where("e = ?", 3)
+
+	b1 := base.Where("f = ?", 4).OrderBy("g")
+	b2 := base.Where("h = ?", 5).Limit(6)
+
+	sql, args, err := base.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = ? AND d = ? AND e = ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, args, err = b1.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = ? AND d = ? AND e = ? AND f = ? ORDER BY g", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, args, err = b2.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = ? AND d = ? AND e = ? AND h = ? LIMIT 6", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 5}, args)
+}
+
+func TestSelectBuilderConcurrent(t *testing.T) {
+	base := Select("a").From("b").Where("c = ?", 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.Column("d").Where("e = ?", i).OrderBy("f").ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, "SELECT a, d FROM b WHERE c = ? AND e = ? ORDER BY f", sql)
+			assert.Equal(t, []interface{}{1, i}, args)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSelectBuilderOrderByClause(t *testing.T) {
+	ids := []int{3, 1, 2}
+	qb := Select("a").
+		From("b").
+		OrderBy("c").
+		OrderByClause("array_position(?, id)", ids).
+		OrderByClause(Case().When(Eq{"d": 1}, "0").Else("1"))
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT a FROM b ORDER BY c, array_position(?, id), CASE WHEN d = ? THEN 0 ELSE 1 END"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{ids, 1}, args)
+}
+
+func TestSelectBuilderDistinctOn(t *testing.T) {
+	qb := Select("user_id", "created_at").
+		DistinctOn("user_id", Expr("date_trunc(?, created_at)", "day")).
+		From("events").
+		Where("kind = ?", "login").
+		OrderByClause("date_trunc(?, created_at) DESC", "day").
+		OrderBy("user_id", "created_at DESC")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT DISTINCT ON (user_id, date_trunc(?, created_at)) user_id, created_at " +
+		"FROM events WHERE kind = ? " +
+		"ORDER BY date_trunc(?, created_at) DESC, user_id, created_at DESC"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{"day", "login", "day"}, args)
+
+	_, _, err = Select("a").DistinctOn("b").From("c").ToSQL()
+	assert.NoError(t, err)
+
+	_, _, err = Select("a").DistinctOn("b", "c").From("d").OrderByClause(Desc("b").NullsLast()).ToSQL()
+	assert.NoError(t, err)
+
+	_, _, err = Select("a").DistinctOn("a").From("t").OrderBy("a, b DESC").ToSQL()
+	assert.NoError(t, err)
+
+	_, _, err = Select("a").DistinctOn("coalesce(a, b)", Expr("f(?)", 1)).From("t").
+		OrderByClause("coalesce(a, b) ASC, f(?) NULLS LAST, g(?)", 1, 2).ToSQL()
+	assert.NoError(t, err)
+}
+
+func TestSelectBuilderDistinctOnErr(t *testing.T) {
+	_, _, err := Select("a").DistinctOn("b").From("c").OrderBy("a", "b").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").DistinctOn("b", "c").From("d").OrderBy("b", "a DESC", "c").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").DistinctOn(Expr("e(?)", 1)).From("d").OrderByClause("e(?)", 2).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").DistinctOn("a").From("t").OrderBy("b, a").ToSQL()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderBindLimitOffset(t *testing.T) {
+	qb := Select("a").From("b").Where("c = ?", 1).Limit(10).Offset(20)
+
+	sql, args, err := qb.BindLimitOffset().ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = ? LIMIT ? OFFSET ?", sql)
+	assert.Equal(t, []interface{}{1, uint64(10), uint64(20)}, args)
+
+	BindLimitOffset = true
+	defer func() { BindLimitOffset = false }()
+
+	sql, args, err = qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = ? LIMIT ? OFFSET ?", sql)
+	assert.Equal(t, []interface{}{1, uint64(10), uint64(20)}, args)
+
+	sql, args, err = Delete("b").Where("c = ?", 1).Limit(10).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM b WHERE ctid IN (SELECT ctid FROM b WHERE c = ? LIMIT ?)", sql)
+	assert.Equal(t, []interface{}{1, uint64(10)}, args)
+}
+
+func TestSelectBuilderLimitClause(t *testing.T) {
+	qb := Select("a").
+		From("b").
+		LimitClause(Select("page_size").From("settings").Where("id = ?", 1)).
+		OffsetClause("? * ?", 2, 10)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b LIMIT (SELECT page_size FROM settings WHERE id = ?) OFFSET ? * ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 10}, args)
+}
+
+func TestSelectBuilderFetchFirst(t *testing.T) {
+	sql, args, err := Select("a").From("b").OrderBy("c").Offset(5).FetchFirstWithTies(10).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b ORDER BY c OFFSET 5 FETCH FIRST 10 ROWS WITH TIES", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Select("a").From("b").FetchFirst(10).BindLimitOffset().ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b FETCH FIRST ? ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{uint64(10)}, args)
+
+	_, _, err = Select("a").From("b").FetchFirstWithTies(10).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("b").Limit(1).FetchFirst(10).ToSQL()
+	assert.Error(t, err)
+}
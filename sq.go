@@ -12,6 +12,7 @@ import (
 
 var (
 	ErrNoRows           = pgx.ErrNoRows
+	ErrTooManyRows      = errors.New("too many rows")
 	ErrTxClosed         = pgx.ErrTxClosed
 	ErrTxCommitRollback = pgx.ErrTxCommitRollback
 )
@@ -23,13 +24,65 @@ type Executor interface {
 	Query(ctx context.Context, qb StatementBuilder) (Rows, error)
 	QueryRow(ctx context.Context, qb StatementBuilder) Row
 	All(ctx context.Context, qb StatementBuilder, dst interface{}) error
+
+	// One scans the single result row into dst, returning ErrNoRows if there
+	// are no rows and ErrTooManyRows if there is more than one.
 	One(ctx context.Context, qb StatementBuilder, dst interface{}) error
+
+	// OneOrNone is like One, but returns false rather than ErrNoRows if there
+	// are no rows.
+	OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error)
+
+	// Exists returns whether qb returns any rows, using SELECT EXISTS (...).
+	Exists(ctx context.Context, qb StatementBuilder) (bool, error)
+
+	// Scalar scans the single column of the single result row into dst.
+	//
+	// See One.
+	Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error
+
+	// Column scans the single column of the result rows into dst, which must be
+	// a pointer to a slice.
+	Column(ctx context.Context, qb StatementBuilder, dst interface{}) error
+
+	// Map scans the two columns of the result rows into dst, which must be a
+	// pointer to a map, as keys and values.
+	Map(ctx context.Context, qb StatementBuilder, dst interface{}) error
+
+	// Notify sends a notification with payload to channel using pg_notify.
+	// Notifications sent in a transaction are delivered when it commits.
+	Notify(ctx context.Context, channel, payload string) error
 }
 
 type Pool interface {
 	Executor
 
 	Tx(ctx context.Context, fn func(tx Tx) error) error
+
+	// Listen subscribes to notifications on channels. The subscription holds a
+	// dedicated connection, which is reconnected and listens again if lost,
+	// until it is closed or ctx is done.
+	//
+	// The connection is opened separately from the pool using its ConnConfig,
+	// so it does not count against MaxConns and the BeforeConnect and
+	// AfterConnect hooks of the pool are not run.
+	Listen(ctx context.Context, channels ...string) (Subscription, error)
+
+	// WithAdvisoryLock calls fn holding the session-level advisory lock key,
+	// waiting for it to be available. The lock is held on a dedicated
+	// connection and released when fn returns, see AdvisoryKey.
+	WithAdvisoryLock(ctx context.Context, key int64, fn func() error) error
+
+	// TryAdvisoryLock is like WithAdvisoryLock, but returns false without
+	// calling fn if the lock is not available.
+	TryAdvisoryLock(ctx context.Context, key int64, fn func() error) (bool, error)
+
+	// Acquire calls fn with a dedicated connection for session-level features
+	// such as temporary tables and SET parameters, which is returned to the
+	// pool when fn returns or panics. Session state is not reset, so fn
+	// should undo any changes that must not leak to other users of the pool.
+	Acquire(ctx context.Context, fn func(conn Conn) error) error
+
 	Close()
 }
 
@@ -64,14 +117,7 @@ type pgxPool struct {
 }
 
 func (p *pgxPool) Tx(ctx context.Context, fn func(tx Tx) error) error {
-	pgxtx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel: pgx.Serializable,
-	})
-	if err != nil {
-		return err
-	}
-	tx := &pgxTx{tx: pgxtx}
-	return txExecute(ctx, tx, fn)
+	return pgxTxExecute(ctx, p.pool, fn)
 }
 
 func (p *pgxPool) Close() {
@@ -98,16 +144,57 @@ func (p *pgxPool) One(ctx context.Context, qb StatementBuilder, dst interface{})
 	return one(ctx, p.pool, qb, dst)
 }
 
+func (p *pgxPool) OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error) {
+	return oneOrNone(ctx, p.pool, qb, dst)
+}
+
+func (p *pgxPool) Notify(ctx context.Context, channel, payload string) error {
+	return notify(ctx, p, channel, payload)
+}
+
+func (p *pgxPool) Exists(ctx context.Context, qb StatementBuilder) (bool, error) {
+	return exists(ctx, p.pool, qb)
+}
+
+func (p *pgxPool) Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return scalar(ctx, p.pool, qb, dst)
+}
+
+func (p *pgxPool) Column(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return column(ctx, p.pool, qb, dst)
+}
+
+func (p *pgxPool) Map(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return mapRows(ctx, p.pool, qb, dst)
+}
+
 type Result = pgconn.CommandTag
 
 type Tx interface {
 	Executor
+
+	// AdvisoryXactLock takes the transaction-level advisory lock key, waiting
+	// for it to be available. The lock is released when the transaction ends.
+	AdvisoryXactLock(ctx context.Context, key int64) error
 }
 
 type pgxTx struct {
 	tx pgx.Tx
 }
 
+func (tx *pgxTx) execSQL(ctx context.Context, sql string) error {
+	_, err := tx.tx.Exec(ctx, sql)
+	return err
+}
+
+func (tx *pgxTx) commit(ctx context.Context) error {
+	return tx.tx.Commit(ctx)
+}
+
+func (tx *pgxTx) rollback(ctx context.Context) error {
+	return tx.tx.Rollback(ctx)
+}
+
 func (tx *pgxTx) Exec(ctx context.Context, qb StatementBuilder) (Result, error) {
 	return exec(ctx, tx.tx, qb)
 }
@@ -128,6 +215,30 @@ func (tx *pgxTx) One(ctx context.Context, qb StatementBuilder, dst interface{})
 	return one(ctx, tx.tx, qb, dst)
 }
 
+func (tx *pgxTx) OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error) {
+	return oneOrNone(ctx, tx.tx, qb, dst)
+}
+
+func (tx *pgxTx) Notify(ctx context.Context, channel, payload string) error {
+	return notify(ctx, tx, channel, payload)
+}
+
+func (tx *pgxTx) Exists(ctx context.Context, qb StatementBuilder) (bool, error) {
+	return exists(ctx, tx.tx, qb)
+}
+
+func (tx *pgxTx) Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return scalar(ctx, tx.tx, qb, dst)
+}
+
+func (tx *pgxTx) Column(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return column(ctx, tx.tx, qb, dst)
+}
+
+func (tx *pgxTx) Map(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return mapRows(ctx, tx.tx, qb, dst)
+}
+
 func exec(ctx context.Context, e pgxExecutor, qb StatementBuilder) (Result, error) {
 	sql, args, err := qb.ToSQL()
 	if err != nil {
@@ -180,12 +291,28 @@ func all(ctx context.Context, e pgxExecutor, qb StatementBuilder, dst interface{
 }
 
 func one(ctx context.Context, e pgxExecutor, qb StatementBuilder, dst interface{}) error {
+	found, err := oneOrNone(ctx, e, qb, dst)
+	if err == nil && !found {
+		err = ErrNoRows
+	}
+	return err
+}
+
+func oneOrNone(ctx context.Context, e pgxExecutor, qb StatementBuilder, dst interface{}) (bool, error) {
 	rows, err := query(ctx, e, qb)
 	if err != nil {
-		return err
+		return false, err
 	}
+	defer rows.Close()
+
+	rs := pgxscan.NewRowScanner(rows)
+	return scanOne(rows, func() error { return rs.Scan(dst) })
+}
 
-	return pgxscan.ScanOne(dst, rows)
+func exists(ctx context.Context, e pgxExecutor, qb StatementBuilder) (bool, error) {
+	var ok bool
+	err := queryRow(ctx, e, Expr("SELECT EXISTS (?)", qb)).Scan(&ok)
+	return ok, err
 }
 
 type pgxExecutor interface {
@@ -203,12 +330,8 @@ func (e rowError) Scan(...interface{}) error {
 }
 
 func IsError(err error, code string) bool {
-	if err == nil {
-		return false
-	}
-
-	var e *pgconn.PgError
-	return errors.As(err, &e) && e != nil && e.Code == code
+	e, ok := AsError(err)
+	return ok && e.Code == code
 }
 
 type Row interface {
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,11 +99,63 @@ func TestPool(t *testing.T) {
 		require.False(t, output5.CreateTime.IsZero())
 
 		err = tx.One(ctx, Select(column1, column2).From(table), &output5)
-		require.EqualError(t, err, "scany: expected 1 row, got: 2")
+		require.True(t, errors.Is(err, ErrTooManyRows))
+		require.EqualError(t, err, "too many rows: expected 1 row, got: 2")
+
+		found, err := tx.OneOrNone(ctx, Select(column1, column2).From(table).Where(Eq{column1: name2}), &output5)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, name2, output5.Name)
+
+		found, err = tx.OneOrNone(ctx, Select(column1, column2).From(table).Where(Eq{column1: "nobody"}), &output5)
+		require.NoError(t, err)
+		require.False(t, found)
+
+		_, err = tx.OneOrNone(ctx, Select(column1, column2).From(table), &output5)
+		require.True(t, errors.Is(err, ErrTooManyRows))
 
 		err = tx.One(ctx, Select(column1, column2).From(table).Offset(5), &output5)
 		require.True(t, errors.Is(err, ErrNoRows))
 
+		var count int
+		err = tx.QueryRow(ctx, CountOf(Select(column1).From(table).OrderBy(column1).Limit(1))).Scan(&count)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		ok, err := tx.Exists(ctx, Select("1").From(table).Where(Eq{column1: name2}))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = tx.Exists(ctx, Select("1").From(table).Where(Eq{column1: "nobody"}))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		var name string
+		err = tx.Scalar(ctx, Select(column1).From(table).Where(Eq{column1: name2}), &name)
+		require.NoError(t, err)
+		require.Equal(t, name2, name)
+
+		err = tx.Scalar(ctx, Select(column1).From(table).Where(Eq{column1: "nobody"}), &name)
+		require.True(t, errors.Is(err, ErrNoRows))
+
+		err = tx.Scalar(ctx, Select(column1).From(table), &name)
+		require.True(t, errors.Is(err, ErrTooManyRows))
+
+		var names []string
+		err = tx.Column(ctx, Select(column1).From(table).OrderBy(column1), &names)
+		require.NoError(t, err)
+		require.Equal(t, []string{name1, name2}, names)
+
+		var createTimes map[string]*time.Time
+		err = tx.Map(ctx, Select(column1, column2).From(table), &createTimes)
+		require.NoError(t, err)
+		require.Len(t, createTimes, 2)
+		require.NotNil(t, createTimes[name1])
+		require.Nil(t, createTimes[name2])
+
+		err = tx.Map(ctx, Select(column1).From(table), &createTimes)
+		require.Error(t, err)
+
 		return nil
 	})
 	require.NoError(t, err)
@@ -119,3 +172,181 @@ func TestPool(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, rows.Next())
 }
+
+func TestListen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	ctx := context.Background()
+
+	pool, err := Connect(ctx, databaseURL())
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	channel := fmt.Sprintf("test_%d", time.Now().Unix())
+
+	sub, err := pool.Listen(ctx, channel)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	require.NoError(t, pool.Notify(ctx, channel, "hello"))
+
+	err = pool.Tx(ctx, func(tx Tx) error {
+		return tx.Notify(ctx, channel, "world")
+	})
+	require.NoError(t, err)
+
+	for _, payload := range []string{"hello", "world"} {
+		select {
+		case n := <-sub.Notifications():
+			require.Equal(t, channel, n.Channel)
+			require.Equal(t, payload, n.Payload)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for notification")
+		}
+	}
+
+	sub.Close()
+	_, ok := <-sub.Notifications()
+	require.False(t, ok)
+}
+
+func TestListenReconnect(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	minDelay := listenMinRetryDelay
+	listenMinRetryDelay = 10 * time.Millisecond
+	defer func() { listenMinRetryDelay = minDelay }()
+
+	ctx := context.Background()
+
+	pool, err := Connect(ctx, databaseURL())
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	channel := fmt.Sprintf("test_reconnect_%d", time.Now().UnixNano())
+
+	sub, err := pool.Listen(ctx, channel)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	var terminated int
+	err = pool.Scalar(ctx, Select("count(pg_terminate_backend(pid))").
+		From("pg_stat_activity").
+		Where("query = ?", "LISTEN "+pgx.Identifier{channel}.Sanitize()), &terminated)
+	require.NoError(t, err)
+	require.Equal(t, 1, terminated)
+
+	// Notifications sent while reconnecting are lost, so keep sending until
+	// one is received on the new connection.
+	timeout := time.After(5 * time.Second)
+	for {
+		require.NoError(t, pool.Notify(ctx, channel, "again"))
+
+		select {
+		case n, ok := <-sub.Notifications():
+			require.True(t, ok)
+			require.Equal(t, channel, n.Channel)
+			require.Equal(t, "again", n.Payload)
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("timed out waiting for notification after reconnecting")
+		}
+	}
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	ctx := context.Background()
+
+	pool, err := Connect(ctx, databaseURL())
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	key := AdvisoryKey(fmt.Sprintf("test_%d", time.Now().UnixNano()))
+
+	err = pool.WithAdvisoryLock(ctx, key, func() error {
+		locked, err := pool.TryAdvisoryLock(ctx, key, func() error {
+			return errors.New("lock acquired twice")
+		})
+		require.NoError(t, err)
+		require.False(t, locked)
+
+		return pool.Tx(ctx, func(tx Tx) error {
+			var locked bool
+			err := tx.QueryRow(ctx, Expr("SELECT pg_try_advisory_xact_lock(?)", key)).Scan(&locked)
+			require.NoError(t, err)
+			require.False(t, locked)
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	locked, err := pool.TryAdvisoryLock(ctx, key, func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	err = pool.Tx(ctx, func(tx Tx) error {
+		return tx.AdvisoryXactLock(ctx, key)
+	})
+	require.NoError(t, err)
+}
+
+func TestAcquire(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	ctx := context.Background()
+
+	config, err := ParseConfig(databaseURL())
+	require.NoError(t, err)
+	config.MaxConns = 1
+
+	pool, err := ConnectConfig(ctx, config)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	err = pool.Acquire(ctx, func(conn Conn) error {
+		_, err := conn.Exec(ctx, Expr("CREATE TEMPORARY TABLE acquire_test (id int)"))
+		require.NoError(t, err)
+
+		err = conn.Tx(ctx, func(tx Tx) error {
+			_, err := tx.Exec(ctx, Insert("acquire_test").Columns("id").Values(1))
+			return err
+		})
+		require.NoError(t, err)
+
+		var count int
+		err = conn.Scalar(ctx, CountOf(Select("id").From("acquire_test")), &count)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		_, err = conn.Exec(ctx, Expr("DROP TABLE acquire_test"))
+		return err
+	})
+	require.NoError(t, err)
+
+	require.Panics(t, func() {
+		_ = pool.Acquire(ctx, func(conn Conn) error {
+			panic("acquire")
+		})
+	})
+
+	// The only connection of the pool must have been released by the panic.
+	acquireCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	err = pool.Acquire(acquireCtx, func(conn Conn) error {
+		ok, err := conn.Exists(ctx, Select("1"))
+		require.True(t, ok)
+		return err
+	})
+	require.NoError(t, err)
+}
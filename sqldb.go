@@ -0,0 +1,438 @@
+package sq
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/georgysavva/scany/sqlscan"
+)
+
+// NewSQLDB returns a Pool backed by a database/sql database, which must use a
+// PostgreSQL driver.
+//
+// Exec returns a Result built from the RowsAffected of the driver, which may
+// not be supported by all drivers. Its command, as checked by Result.Update
+// and similar, is only set for statements built with Insert, Update, Delete,
+// UpdateFromRows and Select. Error helpers such as IsError, and so the
+// retry loop of Tx, require a driver returning *pgconn.PgError errors such as
+// github.com/jackc/pgx/v4/stdlib. Listen is not supported.
+func NewSQLDB(db *sql.DB) Pool {
+	return &sqlDB{db: db}
+}
+
+type sqlDB struct {
+	db *sql.DB
+}
+
+func (p *sqlDB) Tx(ctx context.Context, fn func(tx Tx) error) error {
+	return sqlTxExecute(ctx, p.db, fn)
+}
+
+func (p *sqlDB) Acquire(ctx context.Context, fn func(conn Conn) error) error {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return fn(&sqlConn{conn: conn})
+}
+
+// Listen is not supported by database/sql, which does not expose
+// notifications.
+func (p *sqlDB) Listen(ctx context.Context, channels ...string) (Subscription, error) {
+	return nil, errors.New("listen is not supported by database/sql pools")
+}
+
+func (p *sqlDB) WithAdvisoryLock(ctx context.Context, key int64, fn func() error) error {
+	_, err := p.advisoryLock(ctx, false, key, fn)
+	return err
+}
+
+func (p *sqlDB) TryAdvisoryLock(ctx context.Context, key int64, fn func() error) (bool, error) {
+	return p.advisoryLock(ctx, true, key, fn)
+}
+
+// advisoryLock calls fn holding a session-level advisory lock, on a
+// connection that is pinned until the lock is released.
+func (p *sqlDB) advisoryLock(ctx context.Context, try bool, key int64, fn func() error) (locked bool, err error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if try {
+		err = sqlQueryRow(ctx, conn, Expr("SELECT pg_try_advisory_lock(?)", key)).Scan(&locked)
+	} else {
+		_, err = sqlExec(ctx, conn, Expr("SELECT pg_advisory_lock(?)", key))
+		locked = err == nil
+	}
+	if err != nil || !locked {
+		return false, err
+	}
+
+	defer func() {
+		// The lock must not be returned to the pool with the connection, so it
+		// is released regardless of ctx and the connection is discarded if that
+		// fails, which database/sql does when Raw returns driver.ErrBadConn.
+		if _, unlockErr := sqlExec(context.Background(), conn, Expr("SELECT pg_advisory_unlock(?)", key)); unlockErr != nil {
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			if err == nil {
+				err = unlockErr
+			}
+		}
+	}()
+
+	return true, fn()
+}
+
+func (p *sqlDB) Close() {
+	_ = p.db.Close()
+}
+
+func (p *sqlDB) Exec(ctx context.Context, qb StatementBuilder) (Result, error) {
+	return sqlExec(ctx, p.db, qb)
+}
+
+func (p *sqlDB) Query(ctx context.Context, qb StatementBuilder) (Rows, error) {
+	rows, err := sqlQuery(ctx, p.db, qb)
+	if err != nil {
+		return nil, err
+	}
+	return sqlRows{rows}, nil
+}
+
+func (p *sqlDB) QueryRow(ctx context.Context, qb StatementBuilder) Row {
+	return sqlQueryRow(ctx, p.db, qb)
+}
+
+func (p *sqlDB) All(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlAll(ctx, p.db, qb, dst)
+}
+
+func (p *sqlDB) One(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlOne(ctx, p.db, qb, dst)
+}
+
+func (p *sqlDB) OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error) {
+	return sqlOneOrNone(ctx, p.db, qb, dst)
+}
+
+func (p *sqlDB) Notify(ctx context.Context, channel, payload string) error {
+	return notify(ctx, p, channel, payload)
+}
+
+func (p *sqlDB) Exists(ctx context.Context, qb StatementBuilder) (bool, error) {
+	return sqlExists(ctx, p.db, qb)
+}
+
+func (p *sqlDB) Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, p.db, qb, dst, scanScalar)
+}
+
+func (p *sqlDB) Column(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, p.db, qb, dst, scanColumn)
+}
+
+func (p *sqlDB) Map(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, p.db, qb, dst, scanMap)
+}
+
+type sqlTx struct {
+	tx *sql.Tx
+}
+
+func (tx *sqlTx) execSQL(ctx context.Context, sql string) error {
+	_, err := tx.tx.ExecContext(ctx, sql)
+	return err
+}
+
+func (tx *sqlTx) commit(ctx context.Context) error {
+	return tx.tx.Commit()
+}
+
+func (tx *sqlTx) rollback(ctx context.Context) error {
+	return tx.tx.Rollback()
+}
+
+func (tx *sqlTx) AdvisoryXactLock(ctx context.Context, key int64) error {
+	return advisoryXactLock(ctx, tx, key)
+}
+
+func (tx *sqlTx) Exec(ctx context.Context, qb StatementBuilder) (Result, error) {
+	return sqlExec(ctx, tx.tx, qb)
+}
+
+func (tx *sqlTx) Query(ctx context.Context, qb StatementBuilder) (Rows, error) {
+	rows, err := sqlQuery(ctx, tx.tx, qb)
+	if err != nil {
+		return nil, err
+	}
+	return sqlRows{rows}, nil
+}
+
+func (tx *sqlTx) QueryRow(ctx context.Context, qb StatementBuilder) Row {
+	return sqlQueryRow(ctx, tx.tx, qb)
+}
+
+func (tx *sqlTx) All(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlAll(ctx, tx.tx, qb, dst)
+}
+
+func (tx *sqlTx) One(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlOne(ctx, tx.tx, qb, dst)
+}
+
+func (tx *sqlTx) OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error) {
+	return sqlOneOrNone(ctx, tx.tx, qb, dst)
+}
+
+func (tx *sqlTx) Notify(ctx context.Context, channel, payload string) error {
+	return notify(ctx, tx, channel, payload)
+}
+
+func (tx *sqlTx) Exists(ctx context.Context, qb StatementBuilder) (bool, error) {
+	return sqlExists(ctx, tx.tx, qb)
+}
+
+func (tx *sqlTx) Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, tx.tx, qb, dst, scanScalar)
+}
+
+func (tx *sqlTx) Column(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, tx.tx, qb, dst, scanColumn)
+}
+
+func (tx *sqlTx) Map(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, tx.tx, qb, dst, scanMap)
+}
+
+func sqlExec(ctx context.Context, e sqlExecutor, qb StatementBuilder) (Result, error) {
+	sql, args, err := qb.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	sql, err = replacePlaceholders(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := e.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlResult(qb, res), nil
+}
+
+// sqlResult returns a command tag for a database/sql result, which is the
+// command of the builder followed by the number of affected rows if the driver
+// supports it. Other statements, such as Expr, have only the number of rows.
+func sqlResult(qb StatementBuilder, res sql.Result) Result {
+	var command string
+	switch qb.(type) {
+	case *insertBuilder:
+		command = "INSERT 0"
+	case *updateBuilder, *updateRowsBuilder:
+		command = "UPDATE"
+	case *deleteBuilder:
+		command = "DELETE"
+	case *selectBuilder:
+		command = "SELECT"
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return Result(strings.TrimSuffix(command, " 0"))
+	}
+	if command == "" {
+		return Result(strconv.FormatInt(n, 10))
+	}
+	return Result(fmt.Sprintf("%s %d", command, n))
+}
+
+func sqlQuery(ctx context.Context, e sqlExecutor, qb StatementBuilder) (*sql.Rows, error) {
+	sql, args, err := qb.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	sql, err = replacePlaceholders(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.QueryContext(ctx, sql, args...)
+}
+
+func sqlQueryRow(ctx context.Context, e sqlExecutor, qb StatementBuilder) Row {
+	sql, args, err := qb.ToSQL()
+	if err != nil {
+		return rowError{err}
+	}
+
+	sql, err = replacePlaceholders(sql)
+	if err != nil {
+		return rowError{err}
+	}
+
+	return sqlRow{e.QueryRowContext(ctx, sql, args...)}
+}
+
+func sqlAll(ctx context.Context, e sqlExecutor, qb StatementBuilder, dst interface{}) error {
+	rows, err := sqlQuery(ctx, e, qb)
+	if err != nil {
+		return err
+	}
+
+	return sqlscan.ScanAll(dst, rows)
+}
+
+func sqlOne(ctx context.Context, e sqlExecutor, qb StatementBuilder, dst interface{}) error {
+	found, err := sqlOneOrNone(ctx, e, qb, dst)
+	if err == nil && !found {
+		err = ErrNoRows
+	}
+	return err
+}
+
+func sqlOneOrNone(ctx context.Context, e sqlExecutor, qb StatementBuilder, dst interface{}) (bool, error) {
+	rows, err := sqlQuery(ctx, e, qb)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	rs := sqlscan.NewRowScanner(rows)
+	return scanOne(rows, func() error { return rs.Scan(dst) })
+}
+
+func sqlExists(ctx context.Context, e sqlExecutor, qb StatementBuilder) (bool, error) {
+	var ok bool
+	err := sqlQueryRow(ctx, e, Expr("SELECT EXISTS (?)", qb)).Scan(&ok)
+	return ok, err
+}
+
+// sqlScan scans the result rows of qb into dst with one of the scan functions
+// shared with pgx.
+func sqlScan(ctx context.Context, e sqlExecutor, qb StatementBuilder, dst interface{}, scan func(scanner, int, interface{}) error) error {
+	rows, err := sqlQuery(ctx, e, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	return scan(rows, len(columns), dst)
+}
+
+type sqlConn struct {
+	conn *sql.Conn
+}
+
+func (c *sqlConn) Tx(ctx context.Context, fn func(tx Tx) error) error {
+	return sqlTxExecute(ctx, c.conn, fn)
+}
+
+func (c *sqlConn) Exec(ctx context.Context, qb StatementBuilder) (Result, error) {
+	return sqlExec(ctx, c.conn, qb)
+}
+
+func (c *sqlConn) Query(ctx context.Context, qb StatementBuilder) (Rows, error) {
+	rows, err := sqlQuery(ctx, c.conn, qb)
+	if err != nil {
+		return nil, err
+	}
+	return sqlRows{rows}, nil
+}
+
+func (c *sqlConn) QueryRow(ctx context.Context, qb StatementBuilder) Row {
+	return sqlQueryRow(ctx, c.conn, qb)
+}
+
+func (c *sqlConn) All(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlAll(ctx, c.conn, qb, dst)
+}
+
+func (c *sqlConn) One(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlOne(ctx, c.conn, qb, dst)
+}
+
+func (c *sqlConn) OneOrNone(ctx context.Context, qb StatementBuilder, dst interface{}) (bool, error) {
+	return sqlOneOrNone(ctx, c.conn, qb, dst)
+}
+
+func (c *sqlConn) Notify(ctx context.Context, channel, payload string) error {
+	return notify(ctx, c, channel, payload)
+}
+
+func (c *sqlConn) Exists(ctx context.Context, qb StatementBuilder) (bool, error) {
+	return sqlExists(ctx, c.conn, qb)
+}
+
+func (c *sqlConn) Scalar(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, c.conn, qb, dst, scanScalar)
+}
+
+func (c *sqlConn) Column(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, c.conn, qb, dst, scanColumn)
+}
+
+func (c *sqlConn) Map(ctx context.Context, qb StatementBuilder, dst interface{}) error {
+	return sqlScan(ctx, c.conn, qb, dst, scanMap)
+}
+
+// sqlTxExecute calls fn in a serializable transaction begun by b.
+func sqlTxExecute(ctx context.Context, b sqlBeginner, fn func(tx Tx) error) error {
+	sqltx, err := b.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+	})
+	if err != nil {
+		return err
+	}
+	tx := &sqlTx{tx: sqltx}
+	return txExecute(ctx, tx, fn)
+}
+
+type sqlBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// sqlRow adapts sql.Row to return ErrNoRows rather than sql.ErrNoRows.
+type sqlRow struct {
+	*sql.Row
+}
+
+func (r sqlRow) Scan(dest ...interface{}) error {
+	err := r.Row.Scan(dest...)
+	if err == sql.ErrNoRows {
+		return ErrNoRows
+	}
+	return err
+}
+
+// sqlRows adapts sql.Rows to Rows.
+type sqlRows struct {
+	*sql.Rows
+}
+
+func (r sqlRows) Close() {
+	_ = r.Rows.Close()
+}
@@ -0,0 +1,147 @@
+package sq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sqlResultStub int64
+
+func (r sqlResultStub) LastInsertId() (int64, error) {
+	return 0, errors.New("not supported")
+}
+
+func (r sqlResultStub) RowsAffected() (int64, error) {
+	if r < 0 {
+		return 0, errors.New("not supported")
+	}
+	return int64(r), nil
+}
+
+func TestSQLResult(t *testing.T) {
+	res := sqlResult(Insert("t").Columns("a").Values(1), sqlResultStub(2))
+	assert.Equal(t, "INSERT 0 2", res.String())
+	assert.True(t, res.Insert())
+	assert.Equal(t, int64(2), res.RowsAffected())
+
+	res = sqlResult(Update("t").Prefix("WITH u AS (SELECT 1)").Set("a", 1), sqlResultStub(3))
+	assert.Equal(t, "UPDATE 3", res.String())
+	assert.True(t, res.Update())
+	assert.Equal(t, int64(3), res.RowsAffected())
+
+	res = sqlResult(UpdateFromRows("t", "id", []map[string]interface{}{{"id": 1, "a": 2}}), sqlResultStub(1))
+	assert.Equal(t, "UPDATE 1", res.String())
+	assert.True(t, res.Update())
+
+	res = sqlResult(Delete("t").Prefix("WITH u AS (SELECT 1)"), sqlResultStub(4))
+	assert.Equal(t, "DELETE 4", res.String())
+	assert.True(t, res.Delete())
+
+	res = sqlResult(Expr("WITH u AS (SELECT 1) UPDATE t SET a = 1"), sqlResultStub(5))
+	assert.Equal(t, "5", res.String())
+	assert.Equal(t, int64(5), res.RowsAffected())
+
+	res = sqlResult(Expr("CREATE TABLE t (a int)"), sqlResultStub(-1))
+	assert.Equal(t, "", res.String())
+	assert.Equal(t, int64(0), res.RowsAffected())
+}
+
+func TestSQLDB(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	ctx := context.Background()
+
+	table := fmt.Sprintf("test_sql_%d", time.Now().Unix())
+
+	db, err := sql.Open("pgx", databaseURL())
+	require.NoError(t, err)
+
+	pool := NewSQLDB(db)
+	t.Cleanup(func() {
+		_, err = pool.Exec(ctx, Expr(fmt.Sprintf("DROP TABLE IF EXISTS %s", table)))
+		if err != nil {
+			t.Logf("Failed to drop test table: %s", err.Error())
+		}
+
+		pool.Close()
+	})
+
+	_, err = pool.Exec(ctx, Expr(fmt.Sprintf("CREATE TABLE %s (name text PRIMARY KEY, age int)", table)))
+	require.NoError(t, err)
+
+	attempts := 0
+	err = pool.Tx(ctx, func(tx Tx) error {
+		attempts++
+
+		res, err := tx.Exec(ctx, Insert(table).Columns("name", "age").Values("jane", 30).Values("mike", nil))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), res.RowsAffected())
+
+		if attempts == 1 {
+			return &Error{Code: CodeSerializationFailure}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	type Person struct {
+		Name string
+		Age  *int
+	}
+
+	var people []Person
+	err = pool.All(ctx, Select("name", "age").From(table).OrderBy("name"), &people)
+	require.NoError(t, err)
+	require.Len(t, people, 2)
+	require.Equal(t, "jane", people[0].Name)
+	require.Equal(t, 30, *people[0].Age)
+	require.Nil(t, people[1].Age)
+
+	var person Person
+	err = pool.One(ctx, Select("name", "age").From(table).Where(Eq{"name": "mike"}), &person)
+	require.NoError(t, err)
+	require.Equal(t, "mike", person.Name)
+
+	err = pool.One(ctx, Select("name", "age").From(table), &person)
+	require.True(t, errors.Is(err, ErrTooManyRows))
+
+	var name string
+	err = pool.QueryRow(ctx, Select("name").From(table).Where(Eq{"name": "nobody"})).Scan(&name)
+	require.True(t, errors.Is(err, ErrNoRows))
+
+	err = pool.Scalar(ctx, Select("name").From(table).Where(Eq{"name": "nobody"}), &name)
+	require.True(t, errors.Is(err, ErrNoRows))
+
+	var names []string
+	err = pool.Column(ctx, Select("name").From(table).OrderBy("name"), &names)
+	require.NoError(t, err)
+	require.Equal(t, []string{"jane", "mike"}, names)
+
+	var ages map[string]*int
+	err = pool.Map(ctx, Select("name", "age").From(table), &ages)
+	require.NoError(t, err)
+	require.Len(t, ages, 2)
+
+	ok, err := pool.Exists(ctx, Select("1").From(table).Where(Eq{"name": "jane"}))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = pool.Exec(ctx, Insert(table).Columns("name").Values("jane"))
+	require.True(t, IsError(err, CodeUniqueViolation))
+
+	rows, err := pool.Query(ctx, Select("name").From(table).OrderBy("name"))
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	rows.Close()
+}
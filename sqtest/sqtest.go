@@ -0,0 +1,739 @@
+// Package sqtest provides a fake sq.Pool for unit testing code that executes
+// queries without a database.
+//
+//     db := sqtest.New()
+//     db.Expect(`^SELECT name FROM users WHERE id = \$1$`).
+//         WithArgs(1).
+//         Rows([]string{"name"}, []interface{}{"jane"})
+//     db.Expect(`^UPDATE users`).Error(sq.CodeSerializationFailure)
+//
+//     err := doSomething(ctx, db)
+//     ...
+//     if err := db.ExpectationsWereMet(); err != nil {
+//         t.Error(err)
+//     }
+package sqtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"github.com/georgysavva/scany/dbscan"
+	"github.com/silas/sq"
+	"github.com/silas/sq/internal/placeholder"
+)
+
+// Call is a statement executed by a Fake.
+type Call struct {
+	// SQL is the statement as sent to the database, with numbered
+	// placeholders.
+	SQL  string
+	Args []interface{}
+
+	// Tx is whether the statement was executed in a transaction.
+	Tx bool
+}
+
+// Fake is a fake sq.Pool which matches the statements it executes against
+// expectations. Statements that do not match any expectation return an error.
+//
+// A Fake is safe for concurrent use.
+type Fake struct {
+	mu            sync.Mutex
+	expectations  []*Expectation
+	calls         []Call
+	subscriptions []*subscription
+	locks         map[int64]chan struct{}
+}
+
+var _ sq.Pool = (*Fake)(nil)
+
+// New returns a Fake without any expectations.
+func New() *Fake {
+	return &Fake{}
+}
+
+// Expect adds an expectation for statements matching the regular expression
+// pattern. Statements are matched against the expectations in the order they
+// were added, skipping those that have been used up.
+func (f *Fake) Expect(pattern string) *Expectation {
+	e := &Expectation{pattern: regexp.MustCompile(pattern), times: 1}
+
+	f.mu.Lock()
+	f.expectations = append(f.expectations, e)
+	f.mu.Unlock()
+
+	return e
+}
+
+// Calls returns the statements executed so far.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// ExpectationsWereMet returns an error if any expectation was used fewer times
+// than expected.
+func (f *Fake) ExpectationsWereMet() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range f.expectations {
+		if e.times > 0 && e.used < e.times {
+			return fmt.Errorf("sqtest: expected %d statements matching %q, got %d", e.times, e.pattern, e.used)
+		}
+	}
+	return nil
+}
+
+// Tx calls fn with a fake transaction, retrying on serialization failures
+// like sq.Pool.
+func (f *Fake) Tx(ctx context.Context, fn func(tx sq.Tx) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tx := &fakeExecutor{fake: f, tx: true}
+		err := fn(tx)
+		if err == nil {
+			for _, n := range tx.notifications {
+				f.deliver(n)
+			}
+		}
+		if !sq.IsError(err, sq.CodeSerializationFailure) {
+			return err
+		}
+	}
+}
+
+// Acquire calls fn with a fake connection, which shares the expectations of f.
+func (f *Fake) Acquire(ctx context.Context, fn func(conn sq.Conn) error) error {
+	return fn(&fakeConn{fakeExecutor: f.executor()})
+}
+
+func (f *Fake) Close() {}
+
+func (f *Fake) executor() *fakeExecutor {
+	return &fakeExecutor{fake: f}
+}
+
+func (f *Fake) Exec(ctx context.Context, qb sq.StatementBuilder) (sq.Result, error) {
+	return f.executor().Exec(ctx, qb)
+}
+
+func (f *Fake) Query(ctx context.Context, qb sq.StatementBuilder) (sq.Rows, error) {
+	return f.executor().Query(ctx, qb)
+}
+
+func (f *Fake) QueryRow(ctx context.Context, qb sq.StatementBuilder) sq.Row {
+	return f.executor().QueryRow(ctx, qb)
+}
+
+func (f *Fake) All(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	return f.executor().All(ctx, qb, dst)
+}
+
+func (f *Fake) One(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	return f.executor().One(ctx, qb, dst)
+}
+
+func (f *Fake) OneOrNone(ctx context.Context, qb sq.StatementBuilder, dst interface{}) (bool, error) {
+	return f.executor().OneOrNone(ctx, qb, dst)
+}
+
+func (f *Fake) Notify(ctx context.Context, channel, payload string) error {
+	return f.executor().Notify(ctx, channel, payload)
+}
+
+// Listen returns a subscription to the notifications sent by Notify, which
+// does not require an expectation.
+func (f *Fake) Listen(ctx context.Context, channels ...string) (sq.Subscription, error) {
+	s := &subscription{
+		channels:      make(map[string]bool, len(channels)),
+		notifications: make(chan *sq.Notification, 16),
+		done:          make(chan struct{}),
+	}
+	for _, channel := range channels {
+		s.channels[channel] = true
+	}
+
+	f.mu.Lock()
+	f.subscriptions = append(f.subscriptions, s)
+	f.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+
+	return s, nil
+}
+
+// WithAdvisoryLock calls fn holding an in-memory lock for key, which does not
+// require an expectation.
+func (f *Fake) WithAdvisoryLock(ctx context.Context, key int64, fn func() error) error {
+	f.record(Call{SQL: "SELECT pg_advisory_lock($1)", Args: []interface{}{key}})
+
+	for {
+		released, locked := f.lock(key)
+		if locked {
+			defer f.unlock(key, released)
+			return fn()
+		}
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryAdvisoryLock is like WithAdvisoryLock, but returns false without calling
+// fn if the lock is held.
+func (f *Fake) TryAdvisoryLock(ctx context.Context, key int64, fn func() error) (bool, error) {
+	f.record(Call{SQL: "SELECT pg_try_advisory_lock($1)", Args: []interface{}{key}})
+
+	released, locked := f.lock(key)
+	if !locked {
+		return false, nil
+	}
+	defer f.unlock(key, released)
+
+	return true, fn()
+}
+
+// lock takes the lock for key if it is available, returning a channel that is
+// closed when the lock is released.
+func (f *Fake) lock(key int64) (chan struct{}, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if released, ok := f.locks[key]; ok {
+		return released, false
+	}
+
+	if f.locks == nil {
+		f.locks = make(map[int64]chan struct{})
+	}
+	released := make(chan struct{})
+	f.locks[key] = released
+	return released, true
+}
+
+func (f *Fake) unlock(key int64, released chan struct{}) {
+	f.mu.Lock()
+	delete(f.locks, key)
+	f.mu.Unlock()
+
+	close(released)
+}
+
+func (f *Fake) record(call Call) {
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	f.mu.Unlock()
+}
+
+// deliver sends n to the subscriptions listening on its channel.
+func (f *Fake) deliver(n *sq.Notification) {
+	f.mu.Lock()
+	subscriptions := append([]*subscription(nil), f.subscriptions...)
+	f.mu.Unlock()
+
+	for _, s := range subscriptions {
+		if s.channels[n.Channel] {
+			s.send(n)
+		}
+	}
+}
+
+func (f *Fake) Exists(ctx context.Context, qb sq.StatementBuilder) (bool, error) {
+	return f.executor().Exists(ctx, qb)
+}
+
+func (f *Fake) Scalar(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	return f.executor().Scalar(ctx, qb, dst)
+}
+
+func (f *Fake) Column(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	return f.executor().Column(ctx, qb, dst)
+}
+
+func (f *Fake) Map(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	return f.executor().Map(ctx, qb, dst)
+}
+
+// execute records the statement of qb and returns the expectation it matches.
+func (f *Fake) execute(qb sq.StatementBuilder, tx bool) (*Expectation, error) {
+	sql, args, err := qb.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	sql, err = placeholder.Replace(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{SQL: sql, Args: args, Tx: tx})
+
+	for _, e := range f.expectations {
+		if e.times > 0 && e.used >= e.times {
+			continue
+		}
+		if !e.pattern.MatchString(sql) {
+			continue
+		}
+		if e.args != nil && !reflect.DeepEqual(e.args, args) {
+			continue
+		}
+
+		e.used++
+		return e, e.err
+	}
+
+	return nil, fmt.Errorf("sqtest: unexpected statement %q with args %v", sql, args)
+}
+
+// Expectation is an expected statement and its result.
+type Expectation struct {
+	pattern *regexp.Regexp
+	args    []interface{}
+
+	columns []string
+	rows    [][]interface{}
+	result  sq.Result
+	err     error
+
+	times int
+	used  int
+}
+
+// WithArgs restricts the expectation to statements with args.
+func (e *Expectation) WithArgs(args ...interface{}) *Expectation {
+	if args == nil {
+		args = []interface{}{}
+	}
+	e.args = args
+	return e
+}
+
+// Rows sets the result rows of the statement.
+func (e *Expectation) Rows(columns []string, rows ...[]interface{}) *Expectation {
+	e.columns = columns
+	e.rows = rows
+	return e
+}
+
+// Result sets the command tag returned by Exec, such as "UPDATE 1".
+func (e *Expectation) Result(commandTag string) *Expectation {
+	e.result = sq.Result(commandTag)
+	return e
+}
+
+// Error sets the error returned for the statement, which is a PostgreSQL error
+// with the code if err is a string.
+//
+//     Error(sq.CodeUniqueViolation)
+//     Error(context.DeadlineExceeded)
+func (e *Expectation) Error(err interface{}) *Expectation {
+	switch err := err.(type) {
+	case string:
+		e.err = &sq.Error{Severity: "ERROR", Code: err, Message: sq.ConditionName(err)}
+	case error:
+		e.err = err
+	default:
+		panic(fmt.Sprintf("sqtest: expected string or error, not %T", err))
+	}
+	return e
+}
+
+// Times sets the number of statements the expectation matches, which
+// defaults to 1. Zero matches any number of statements.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+type fakeExecutor struct {
+	fake *Fake
+	tx   bool
+
+	// notifications are the notifications sent in a transaction, which are
+	// delivered when it commits.
+	notifications []*sq.Notification
+}
+
+// AdvisoryXactLock records the lock, which does not require an expectation.
+func (x *fakeExecutor) AdvisoryXactLock(ctx context.Context, key int64) error {
+	x.fake.record(Call{
+		SQL:  "SELECT pg_advisory_xact_lock($1)",
+		Args: []interface{}{key},
+		Tx:   x.tx,
+	})
+	return nil
+}
+
+func (x *fakeExecutor) Notify(ctx context.Context, channel, payload string) error {
+	x.fake.record(Call{
+		SQL:  "SELECT pg_notify($1, $2)",
+		Args: []interface{}{channel, payload},
+		Tx:   x.tx,
+	})
+
+	n := &sq.Notification{Channel: channel, Payload: payload}
+	if x.tx {
+		x.notifications = append(x.notifications, n)
+	} else {
+		x.fake.deliver(n)
+	}
+	return nil
+}
+
+func (x *fakeExecutor) Exec(ctx context.Context, qb sq.StatementBuilder) (sq.Result, error) {
+	e, err := x.fake.execute(qb, x.tx)
+	if err != nil {
+		return nil, err
+	}
+	return e.result, nil
+}
+
+func (x *fakeExecutor) query(qb sq.StatementBuilder) (*rows, error) {
+	e, err := x.fake.execute(qb, x.tx)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{columns: e.columns, rows: e.rows, index: -1}, nil
+}
+
+func (x *fakeExecutor) Query(ctx context.Context, qb sq.StatementBuilder) (sq.Rows, error) {
+	return x.query(qb)
+}
+
+func (x *fakeExecutor) QueryRow(ctx context.Context, qb sq.StatementBuilder) sq.Row {
+	r, err := x.query(qb)
+	if err != nil {
+		return errorRow{err}
+	}
+	return r
+}
+
+func (x *fakeExecutor) All(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	r, err := x.query(qb)
+	if err != nil {
+		return err
+	}
+	return dbscan.ScanAll(dst, dbscanRows{r})
+}
+
+func (x *fakeExecutor) One(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	found, err := x.OneOrNone(ctx, qb, dst)
+	if err == nil && !found {
+		err = sq.ErrNoRows
+	}
+	return err
+}
+
+func (x *fakeExecutor) OneOrNone(ctx context.Context, qb sq.StatementBuilder, dst interface{}) (bool, error) {
+	r, err := x.query(qb)
+	if err != nil {
+		return false, err
+	}
+	if len(r.rows) > 1 {
+		return false, fmt.Errorf("%w: expected 1 row, got: %d", sq.ErrTooManyRows, len(r.rows))
+	}
+	if !r.Next() {
+		return false, nil
+	}
+	return true, dbscan.NewRowScanner(dbscanRows{r}).Scan(dst)
+}
+
+func (x *fakeExecutor) Exists(ctx context.Context, qb sq.StatementBuilder) (bool, error) {
+	var ok bool
+	err := x.QueryRow(ctx, sq.Expr("SELECT EXISTS (?)", qb)).Scan(&ok)
+	return ok, err
+}
+
+func (x *fakeExecutor) Scalar(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	r, err := x.query(qb)
+	if err != nil {
+		return err
+	}
+	if err := checkColumns(r, 1); err != nil {
+		return err
+	}
+	if len(r.rows) > 1 {
+		return fmt.Errorf("%w: expected 1 row, got: %d", sq.ErrTooManyRows, len(r.rows))
+	}
+	return r.Scan(dst)
+}
+
+func (x *fakeExecutor) Column(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	r, err := x.query(qb)
+	if err != nil {
+		return err
+	}
+	if err := checkColumns(r, 1); err != nil {
+		return err
+	}
+
+	sliceVal := reflect.ValueOf(dst)
+	if sliceVal.Kind() != reflect.Ptr || sliceVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected pointer to slice, not %T", dst)
+	}
+	sliceVal = sliceVal.Elem()
+
+	sliceVal.Set(reflect.MakeSlice(sliceVal.Type(), 0, len(r.rows)))
+	for r.Next() {
+		elem := reflect.New(sliceVal.Type().Elem())
+		if err := r.Scan(elem.Interface()); err != nil {
+			return err
+		}
+		sliceVal.Set(reflect.Append(sliceVal, elem.Elem()))
+	}
+	return nil
+}
+
+func (x *fakeExecutor) Map(ctx context.Context, qb sq.StatementBuilder, dst interface{}) error {
+	r, err := x.query(qb)
+	if err != nil {
+		return err
+	}
+	if err := checkColumns(r, 2); err != nil {
+		return err
+	}
+
+	mapVal := reflect.ValueOf(dst)
+	if mapVal.Kind() != reflect.Ptr || mapVal.Elem().Kind() != reflect.Map {
+		return fmt.Errorf("expected pointer to map, not %T", dst)
+	}
+	mapVal = mapVal.Elem()
+
+	mapVal.Set(reflect.MakeMap(mapVal.Type()))
+	for r.Next() {
+		key := reflect.New(mapVal.Type().Key())
+		elem := reflect.New(mapVal.Type().Elem())
+		if err := r.Scan(key.Interface(), elem.Interface()); err != nil {
+			return err
+		}
+		mapVal.SetMapIndex(key.Elem(), elem.Elem())
+	}
+	return nil
+}
+
+func checkColumns(r *rows, n int) error {
+	if len(r.columns) != n {
+		return fmt.Errorf("expected %d result columns, got: %d", n, len(r.columns))
+	}
+	return nil
+}
+
+type fakeConn struct {
+	*fakeExecutor
+}
+
+func (c *fakeConn) Tx(ctx context.Context, fn func(tx sq.Tx) error) error {
+	return c.fake.Tx(ctx, fn)
+}
+
+// subscription is a subscription to the notifications of a Fake. Delivery
+// blocks once its buffer is full until the notifications are received.
+type subscription struct {
+	channels      map[string]bool
+	notifications chan *sq.Notification
+	done          chan struct{}
+	closeOnce     sync.Once
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *subscription) send(n *sq.Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.notifications <- n:
+	case <-s.done:
+	}
+}
+
+func (s *subscription) Notifications() <-chan *sq.Notification {
+	return s.notifications
+}
+
+func (s *subscription) Run(ctx context.Context, fn func(n *sq.Notification) error) error {
+	for {
+		select {
+		case n, ok := <-s.notifications:
+			if !ok {
+				return nil
+			}
+			if err := fn(n); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *subscription) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		s.closed = true
+		close(s.notifications)
+		s.mu.Unlock()
+	})
+}
+
+type errorRow struct {
+	err error
+}
+
+func (r errorRow) Scan(...interface{}) error {
+	return r.err
+}
+
+// rows are the configured rows of an expectation, which are also a sq.Row
+// scanning the first row.
+type rows struct {
+	columns []string
+	rows    [][]interface{}
+	index   int
+}
+
+func (r *rows) Next() bool {
+	if r.index+1 >= len(r.rows) {
+		r.index = len(r.rows)
+		return false
+	}
+	r.index++
+	return true
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	if r.index == -1 && !r.Next() {
+		return sq.ErrNoRows
+	}
+	if r.index >= len(r.rows) {
+		return errors.New("sqtest: no current row")
+	}
+
+	row := r.rows[r.index]
+	if len(dest) != len(row) {
+		return fmt.Errorf("sqtest: expected %d destinations, got %d", len(row), len(dest))
+	}
+
+	for i, d := range dest {
+		if err := assign(d, row[i]); err != nil {
+			return fmt.Errorf("sqtest: scan column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r *rows) Close() {}
+
+// dbscanRows adapts rows to dbscan.Rows.
+type dbscanRows struct {
+	*rows
+}
+
+func (r dbscanRows) Close() error {
+	return nil
+}
+
+func (r dbscanRows) Err() error {
+	return nil
+}
+
+func (r dbscanRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+type scanner interface {
+	Scan(src interface{}) error
+}
+
+// assign stores value in the pointer dest like a database driver.
+func assign(dest, value interface{}) error {
+	if s, ok := dest.(scanner); ok {
+		return s.Scan(value)
+	}
+
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.IsNil() {
+		return fmt.Errorf("expected non-nil pointer destination, not %T", dest)
+	}
+
+	return assignValue(destVal.Elem(), value)
+}
+
+func assignValue(dst reflect.Value, value interface{}) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(dst.Type()):
+		dst.Set(v)
+	case dst.Kind() == reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		return assignValue(dst, v.Elem().Interface())
+	case convertible(v.Kind(), dst.Kind()):
+		dst.Set(v.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("cannot assign %T to %s", value, dst.Type())
+	}
+	return nil
+}
+
+// convertible returns whether values of kind from are converted to kind to,
+// which is limited to numbers and strings.
+func convertible(from, to reflect.Kind) bool {
+	class := func(k reflect.Kind) int {
+		switch k {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return 1
+		case reflect.String:
+			return 2
+		default:
+			return 0
+		}
+	}
+	return class(from) != 0 && class(from) == class(to)
+}
@@ -0,0 +1,252 @@
+package sqtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/silas/sq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeQuery(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Expect(`^SELECT id, name, created_at FROM users WHERE active = \$1 ORDER BY id$`).
+		WithArgs(true).
+		Rows([]string{"id", "name", "created_at"},
+			[]interface{}{1, "jane", created},
+			[]interface{}{2, "mike", nil})
+
+	type User struct {
+		ID        int64
+		Name      string
+		CreatedAt *time.Time
+	}
+
+	var users []User
+	err := db.All(ctx, sq.Select("id", "name", "created_at").From("users").Where("active = ?", true).OrderBy("id"), &users)
+	require.NoError(t, err)
+	assert.Equal(t, []User{{ID: 1, Name: "jane", CreatedAt: &created}, {ID: 2, Name: "mike"}}, users)
+
+	assert.Equal(t, []Call{{
+		SQL:  "SELECT id, name, created_at FROM users WHERE active = $1 ORDER BY id",
+		Args: []interface{}{true},
+	}}, db.Calls())
+	assert.NoError(t, db.ExpectationsWereMet())
+
+	err = db.All(ctx, sq.Select("id").From("users"), &users)
+	assert.EqualError(t, err, `sqtest: unexpected statement "SELECT id FROM users" with args []`)
+}
+
+func TestFakeOne(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+
+	db.Expect(`FROM users`).WithArgs(1).Rows([]string{"name"}, []interface{}{"jane"}).Times(2)
+	db.Expect(`FROM users`).WithArgs(2).Rows([]string{"name"})
+	db.Expect(`FROM users`).WithArgs(3).Rows([]string{"name"}, []interface{}{"a"}, []interface{}{"b"})
+
+	var user struct{ Name string }
+	err := db.One(ctx, sq.Select("name").From("users").Where(sq.Eq{"id": 1}), &user)
+	require.NoError(t, err)
+	assert.Equal(t, "jane", user.Name)
+
+	var name string
+	err = db.QueryRow(ctx, sq.Select("name").From("users").Where(sq.Eq{"id": 1})).Scan(&name)
+	require.NoError(t, err)
+	assert.Equal(t, "jane", name)
+
+	found, err := db.OneOrNone(ctx, sq.Select("name").From("users").Where(sq.Eq{"id": 2}), &user)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	err = db.One(ctx, sq.Select("name").From("users").Where(sq.Eq{"id": 3}), &user)
+	assert.True(t, errors.Is(err, sq.ErrTooManyRows))
+
+	assert.NoError(t, db.ExpectationsWereMet())
+}
+
+func TestFakeScan(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+
+	db.Expect(`^SELECT EXISTS`).Rows([]string{"exists"}, []interface{}{true})
+	db.Expect(`count`).Rows([]string{"count"}, []interface{}{int64(2)})
+	db.Expect(`SELECT name FROM`).Rows([]string{"name"}, []interface{}{"a"}, []interface{}{"b"})
+	db.Expect(`SELECT name, age FROM`).Rows([]string{"name", "age"}, []interface{}{"a", 1}, []interface{}{"b", nil})
+
+	ok, err := db.Exists(ctx, sq.Select("1").From("users"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	var count int
+	err = db.Scalar(ctx, sq.CountOf(sq.Select("id").From("users")), &count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	var names []string
+	err = db.Column(ctx, sq.Select("name").From("users"), &names)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	var ages map[string]*int
+	err = db.Map(ctx, sq.Select("name", "age").From("users"), &ages)
+	require.NoError(t, err)
+	one := 1
+	assert.Equal(t, map[string]*int{"a": &one, "b": nil}, ages)
+}
+
+func TestFakeTx(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+
+	db.Expect(`^UPDATE accounts`).Error(sq.CodeSerializationFailure)
+	db.Expect(`^UPDATE accounts`).Result("UPDATE 1")
+	db.Expect(`^INSERT INTO accounts`).Error(sq.CodeUniqueViolation)
+
+	attempts := 0
+	err := db.Tx(ctx, func(tx sq.Tx) error {
+		attempts++
+		res, err := tx.Exec(ctx, sq.Update("accounts").Set("balance", 0).Where(sq.Eq{"id": 1}))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, int64(1), res.RowsAffected())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	calls := db.Calls()
+	require.Len(t, calls, 2)
+	assert.True(t, calls[1].Tx)
+
+	_, err = db.Exec(ctx, sq.Insert("accounts").Columns("id").Values(1))
+	e, ok := sq.AsError(err)
+	require.True(t, ok)
+	assert.Equal(t, "unique_violation", e.Message)
+	assert.True(t, sq.IsIntegrityViolation(err))
+
+	assert.NoError(t, db.ExpectationsWereMet())
+	db.Expect(`^DELETE`)
+	assert.Error(t, db.ExpectationsWereMet())
+}
+
+func TestFakeTxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := New()
+
+	db.Expect(`^UPDATE accounts`).Error(sq.CodeSerializationFailure).Times(0)
+
+	attempts := 0
+	err := db.Tx(ctx, func(tx sq.Tx) error {
+		attempts++
+		if attempts == 3 {
+			cancel()
+		}
+		_, err := tx.Exec(ctx, sq.Update("accounts").Set("balance", 0))
+		return err
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestFakeListen(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+
+	sub, err := db.Listen(ctx, "users")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Notify(ctx, "users", "1"))
+	require.NoError(t, db.Notify(ctx, "orders", "2"))
+
+	err = db.Tx(ctx, func(tx sq.Tx) error {
+		require.NoError(t, tx.Notify(ctx, "users", "3"))
+		assert.Len(t, sub.Notifications(), 1, "notification delivered before commit")
+		return nil
+	})
+	require.NoError(t, err)
+
+	var payloads []string
+	err = sub.Run(ctx, func(n *sq.Notification) error {
+		payloads = append(payloads, n.Payload)
+		if len(payloads) == 2 {
+			sub.Close()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, payloads)
+
+	calls := db.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, Call{SQL: "SELECT pg_notify($1, $2)", Args: []interface{}{"users", "3"}, Tx: true}, calls[2])
+}
+
+func TestFakeAdvisoryLock(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	key := sq.AdvisoryKey("jobs")
+
+	err := db.WithAdvisoryLock(ctx, key, func() error {
+		locked, err := db.TryAdvisoryLock(ctx, key, func() error {
+			t.Error("lock acquired twice")
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.False(t, locked)
+
+		locked, err = db.TryAdvisoryLock(ctx, key+1, func() error { return nil })
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, db.WithAdvisoryLock(ctx, key, func() error { return nil }))
+		return nil
+	})
+	require.NoError(t, err)
+
+	locked, err := db.TryAdvisoryLock(ctx, key, func() error { return nil })
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	err = db.Tx(ctx, func(tx sq.Tx) error {
+		return tx.AdvisoryXactLock(ctx, key)
+	})
+	require.NoError(t, err)
+
+	calls := db.Calls()
+	assert.Equal(t, Call{SQL: "SELECT pg_advisory_xact_lock($1)", Args: []interface{}{key}, Tx: true}, calls[len(calls)-1])
+}
+
+func TestFakeAcquire(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+
+	db.Expect(`^SET search_path`)
+	db.Expect(`^SELECT id FROM users$`).Rows([]string{"id"}, []interface{}{1})
+
+	err := db.Acquire(ctx, func(conn sq.Conn) error {
+		if _, err := conn.Exec(ctx, sq.Expr("SET search_path TO tenant")); err != nil {
+			return err
+		}
+		return conn.Tx(ctx, func(tx sq.Tx) error {
+			var ids []int
+			return tx.Column(ctx, sq.Select("id").From("users"), &ids)
+		})
+	})
+	require.NoError(t, err)
+	assert.NoError(t, db.ExpectationsWereMet())
+
+	calls := db.Calls()
+	require.Len(t, calls, 2)
+	assert.False(t, calls[0].Tx)
+	assert.True(t, calls[1].Tx)
+}
@@ -0,0 +1,72 @@
+package sq
+
+import "fmt"
+
+// TextSearch builds a full-text search predicate matching column against a
+// websearch_to_tsquery query. If config is empty the server's
+// default_text_search_config is used. Config is rendered as a literal, so an
+// expression index on to_tsvector('english', body) can be used.
+//
+//     .Where(TextSearch("body", "english", "fat rats")) ==
+//         "to_tsvector('english', body) @@ websearch_to_tsquery('english', ?)"
+func TextSearch(column, config, query string) StatementBuilder {
+	vecSQL, vecArgs := tsVector(column, config)
+	querySQL, queryArgs := tsQuery(config, query)
+	return Expr(fmt.Sprintf("%s @@ %s", vecSQL, querySQL), append(vecArgs, queryArgs...)...)
+}
+
+// TSRank builds a ts_rank expression ranking column against a
+// websearch_to_tsquery query.
+//
+//     .OrderByClause(Desc(TSRank("body", "english", "fat rats")))
+//
+// See TextSearch.
+func TSRank(column, config, query string) StatementBuilder {
+	vecSQL, vecArgs := tsVector(column, config)
+	querySQL, queryArgs := tsQuery(config, query)
+	return Expr(fmt.Sprintf("ts_rank(%s, %s)", vecSQL, querySQL), append(vecArgs, queryArgs...)...)
+}
+
+// TSHeadline builds a ts_headline expression highlighting the matches of a
+// websearch_to_tsquery query in column. Options is passed as the ts_headline
+// options string if not empty.
+//
+//     .Column(Alias(TSHeadline("body", "english", "fat rats", "MaxWords=10"), "headline"))
+//
+// See TextSearch.
+func TSHeadline(column, config, query, options string) StatementBuilder {
+	var (
+		sql  string
+		args []interface{}
+	)
+
+	querySQL, queryArgs := tsQuery(config, query)
+
+	if config != "" {
+		sql = fmt.Sprintf("ts_headline(%s, %s, %s", quoteLiteral(config), column, querySQL)
+	} else {
+		sql = fmt.Sprintf("ts_headline(%s, %s", column, querySQL)
+	}
+	args = queryArgs
+
+	if options != "" {
+		sql += ", ?"
+		args = append(args, options)
+	}
+
+	return Expr(sql+")", args...)
+}
+
+func tsVector(column, config string) (string, []interface{}) {
+	if config == "" {
+		return fmt.Sprintf("to_tsvector(%s)", column), nil
+	}
+	return fmt.Sprintf("to_tsvector(%s, %s)", quoteLiteral(config), column), nil
+}
+
+func tsQuery(config, query string) (string, []interface{}) {
+	if config == "" {
+		return "websearch_to_tsquery(?)", []interface{}{query}
+	}
+	return fmt.Sprintf("websearch_to_tsquery(%s, ?)", quoteLiteral(config)), []interface{}{query}
+}
@@ -0,0 +1,54 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextSearch(t *testing.T) {
+	qb := Select("id").
+		Column(Alias(TSHeadline("body", "english", "fat rats", "MaxWords=10"), "headline")).
+		From("docs").
+		Where(TextSearch("body", "english", "fat rats")).
+		Where("deleted = ?", false).
+		OrderByClause("? DESC", TSRank("body", "english", "fat rats")).
+		OrderBy("id")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT id, " +
+		"(ts_headline('english', body, websearch_to_tsquery('english', ?), ?)) AS headline " +
+		"FROM docs " +
+		"WHERE to_tsvector('english', body) @@ websearch_to_tsquery('english', ?) AND deleted = ? " +
+		"ORDER BY ts_rank(to_tsvector('english', body), websearch_to_tsquery('english', ?)) DESC, id"
+	assert.Equal(t, expectedSQL, sql)
+
+	expectedArgs := []interface{}{
+		"fat rats", "MaxWords=10",
+		"fat rats", false,
+		"fat rats",
+	}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestTextSearchQuotedConfig(t *testing.T) {
+	sql, args, err := TextSearch("body", "it's?", "rats").ToSQL()
+	assert.NoError(t, err)
+	sql, err = replacePlaceholders(sql)
+	assert.NoError(t, err)
+	assert.Equal(t, "to_tsvector('it''s?', body) @@ websearch_to_tsquery('it''s?', $1)", sql)
+	assert.Equal(t, []interface{}{"rats"}, args)
+}
+
+func TestTextSearchDefaultConfig(t *testing.T) {
+	sql, args, err := TextSearch("body", "", "rats").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "to_tsvector(body) @@ websearch_to_tsquery(?)", sql)
+	assert.Equal(t, []interface{}{"rats"}, args)
+
+	sql, args, err = TSHeadline("body", "", "rats", "").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "ts_headline(body, websearch_to_tsquery(?))", sql)
+	assert.Equal(t, []interface{}{"rats"}, args)
+}
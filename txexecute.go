@@ -18,20 +18,29 @@ import (
 	"context"
 )
 
-func txExecute(ctx context.Context, tx *pgxTx, fn func(Tx) error) (err error) {
+// driverTx is a transaction of one of the supported drivers.
+type driverTx interface {
+	Tx
+
+	execSQL(ctx context.Context, sql string) error
+	commit(ctx context.Context) error
+	rollback(ctx context.Context) error
+}
+
+func txExecute(ctx context.Context, tx driverTx, fn func(Tx) error) (err error) {
 	defer func() {
 		if err == nil {
 			// Ignore commit errors. The tx has already been committed by RELEASE.
-			_ = tx.tx.Commit(ctx)
+			_ = tx.commit(ctx)
 		} else {
 			// We always need to execute a Rollback() so sql.DB releases the
 			// connection.
-			_ = tx.tx.Rollback(ctx)
+			_ = tx.rollback(ctx)
 		}
 	}()
 	// Specify that we intend to retry this txn in case of CockroachDB retryable
 	// errors.
-	if _, err = tx.tx.Exec(ctx, "SAVEPOINT sq"); err != nil {
+	if err = tx.execSQL(ctx, "SAVEPOINT sq"); err != nil {
 		return err
 	}
 
@@ -40,7 +49,7 @@ func txExecute(ctx context.Context, tx *pgxTx, fn func(Tx) error) (err error) {
 		if err == nil {
 			// RELEASE acts like COMMIT in CockroachDB. We use it since it gives us an
 			// opportunity to react to retryable errors, whereas tx.Commit() doesn't.
-			if _, err = tx.tx.Exec(ctx, "RELEASE SAVEPOINT sq"); err == nil {
+			if err = tx.execSQL(ctx, "RELEASE SAVEPOINT sq"); err == nil {
 				return nil
 			}
 		}
@@ -49,7 +58,7 @@ func txExecute(ctx context.Context, tx *pgxTx, fn func(Tx) error) (err error) {
 			return err
 		}
 
-		if _, retryErr := tx.tx.Exec(ctx, "ROLLBACK TO SAVEPOINT sq"); retryErr != nil {
+		if retryErr := tx.execSQL(ctx, "ROLLBACK TO SAVEPOINT sq"); retryErr != nil {
 			return err
 		}
 	}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 )
 
@@ -14,6 +13,8 @@ type setClause struct {
 }
 
 // UpdateBuilder builds SQL UPDATE statements.
+//
+// Like SelectBuilder, UpdateBuilder is immutable.
 type UpdateBuilder interface {
 	// Prefix adds an expression to the beginning of the query.
 	Prefix(sql string, args ...interface{}) UpdateBuilder
@@ -24,26 +25,82 @@ type UpdateBuilder interface {
 	// Set adds SET clauses to the query.
 	Set(column string, value interface{}) UpdateBuilder
 
+	// SetColumns adds a multiple-column SET clause to the query, assigning the
+	// row value of value to columns. Value is wrapped in parentheses, so it may be
+	// a subquery or a list of expressions. With a single column a list of
+	// expressions is wrapped in ROW(...), as PostgreSQL requires, unless value
+	// is a subquery: a SelectBuilder, a ValuesBuilder or SQL starting with
+	// SELECT, VALUES, WITH or TABLE.
+	//
+	//   SetColumns([]string{"a", "b"}, Expr("?, ?", 1, 2)) == "(a, b) = (1, 2)"
+	//   SetColumns([]string{"a"}, Expr("?", 1)) == "(a) = ROW(1)"
+	//   SetColumns([]string{"a"}, Values([]interface{}{1})) == "(a) = (VALUES (1))"
+	//   SetColumns([]string{"a", "b"}, Select("x", "y").From("c").Where("c.id = t.c_id"))
+	SetColumns(columns []string, value StatementBuilder) UpdateBuilder
+
+	// SetDefault adds a SET clause assigning the column its default value.
+	SetDefault(column string) UpdateBuilder
+
+	// SetJSON adds a SET clause replacing the value at path in the jsonb column
+	// using jsonb_set. The value is encoded with encoding/json unless it is a
+	// StatementBuilder.
+	//
+	//   SetJSON("data", []string{"a", "b"}, 1) == "data = jsonb_set(data, '{a,b}', '1')"
+	SetJSON(column string, path []string, value interface{}) UpdateBuilder
+
 	// SetMap is a convenience method which calls .Set for each key/value pair in clauses.
 	SetMap(clauses map[string]interface{}) UpdateBuilder
 
 	// From adds FROM clause to the query.
 	From(from string) UpdateBuilder
 
+	// FromClause adds a FROM expression such as a subquery or a VALUES list to
+	// the query.
+	//
+	// See SelectBuilder.FromClause.
+	FromClause(pred interface{}, args ...interface{}) UpdateBuilder
+
 	// Where adds WHERE expressions to the query.
 	//
 	// See SelectBuilder.Where for more information.
 	Where(pred interface{}, args ...interface{}) UpdateBuilder
 
-	// OrderBy adds ORDER BY expressions to the query.
+	// WhereCurrentOf sets a WHERE CURRENT OF clause on the query, updating the
+	// row most recently fetched from cursor. It cannot be combined with Where.
+	WhereCurrentOf(cursor string) UpdateBuilder
+
+	// OrderBy adds ORDER BY expressions to the query. It requires Limit or
+	// Offset, see Limit.
 	OrderBy(orderBys ...string) UpdateBuilder
 
+	// OrderByClause adds an ORDER BY expression to the query.
+	//
+	// See SelectBuilder.OrderByClause.
+	OrderByClause(pred interface{}, args ...interface{}) UpdateBuilder
+
 	// Limit sets a LIMIT clause on the query.
+	//
+	// PostgreSQL does not support ORDER BY, LIMIT and OFFSET in UPDATE
+	// statements, so they are emulated by restricting the WHERE clause to the
+	// rows selected by a subquery, identified by their ctid or the LimitKey
+	// columns:
+	//
+	//   Update("t").Set("a", 1).Where("b").OrderBy("c").Limit(10) ==
+	//     "UPDATE t SET a = ? WHERE ctid IN (SELECT ctid FROM t WHERE b ORDER BY c LIMIT 10)"
 	Limit(limit uint64) UpdateBuilder
 
 	// Offset sets a OFFSET clause on the query.
+	//
+	// See Limit.
 	Offset(offset uint64) UpdateBuilder
 
+	// LimitKey sets the columns identifying rows when emulating ORDER BY, LIMIT
+	// and OFFSET, which defaults to ctid. A primary key should be used for
+	// partitioned tables, where ctid is not unique.
+	//
+	// See Limit.
+	LimitKey(columns ...string) UpdateBuilder
+
 	// Suffix adds an expression to the end of the query.
 	Suffix(sql string, args ...interface{}) UpdateBuilder
 
@@ -54,14 +111,16 @@ type updateBuilder struct {
 	prefixes   exprs
 	table      string
 	setClauses []setClause
-	from       []string
+	from       []StatementBuilder
 	whereParts []StatementBuilder
-	orderBys   []string
+	cursor     string
+	orderBys   []StatementBuilder
 
 	limit       uint64
 	limitValid  bool
 	offset      uint64
 	offsetValid bool
+	limitKeys   []string
 
 	suffixes exprs
 }
@@ -71,6 +130,18 @@ func NewUpdateBuilder() UpdateBuilder {
 	return &updateBuilder{}
 }
 
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *updateBuilder) clone() *updateBuilder {
+	nb := *b
+	nb.prefixes = nb.prefixes[:len(nb.prefixes):len(nb.prefixes)]
+	nb.setClauses = nb.setClauses[:len(nb.setClauses):len(nb.setClauses)]
+	nb.from = nb.from[:len(nb.from):len(nb.from)]
+	nb.whereParts = nb.whereParts[:len(nb.whereParts):len(nb.whereParts)]
+	nb.orderBys = nb.orderBys[:len(nb.orderBys):len(nb.orderBys)]
+	nb.suffixes = nb.suffixes[:len(nb.suffixes):len(nb.suffixes)]
+	return &nb
+}
+
 func (b *updateBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	if len(b.table) == 0 {
 		err = fmt.Errorf("update statements must specify a table")
@@ -84,7 +155,10 @@ func (b *updateBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSQL(sql, " ", args)
+		args, err = b.prefixes.AppendToSQL(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -113,63 +187,158 @@ func (b *updateBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.from) > 0 {
 		sql.WriteString(" FROM ")
-		for i, from := range b.from {
-			if i > 0 {
-				sql.WriteString(", ")
-			}
-			sql.WriteString(from)
+		args, err = appendToSQL(b.from, sql, ", ", args)
+		if err != nil {
+			return
 		}
 	}
 
-	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSQL(b.whereParts, sql, " AND ", args)
-		if err != nil {
+	if len(b.cursor) > 0 {
+		if len(b.whereParts) > 0 {
+			err = fmt.Errorf("update statements cannot have both WHERE CURRENT OF and Where clauses")
 			return
 		}
+
+		sql.WriteString(" WHERE CURRENT OF ")
+		sql.WriteString(b.cursor)
 	}
 
-	if len(b.orderBys) > 0 {
-		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+	whereParts := b.whereParts
+	if len(b.orderBys) > 0 && !b.limitValid && !b.offsetValid {
+		err = fmt.Errorf("update statements with ORDER BY must also have LIMIT or OFFSET")
+		return
 	}
+	if b.limitValid || b.offsetValid {
+		if len(b.from) > 0 || len(b.cursor) > 0 {
+			err = fmt.Errorf("update statements with ORDER BY, LIMIT or OFFSET cannot have FROM or WHERE CURRENT OF clauses")
+			return
+		}
 
-	// TODO: limit == 0 and offset == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+		whereParts = []StatementBuilder{limitPredicate(b.table, b.limitKeys,
+			limitSelect(b.whereParts, b.orderBys, b.limit, b.limitValid, b.offset, b.offsetValid))}
 	}
 
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+	if len(whereParts) > 0 {
+		sql.WriteString(" WHERE ")
+		args, err = appendToSQL(whereParts, sql, " AND ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSQL(sql, " ", args)
+		args, err = b.suffixes.AppendToSQL(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	sqlStr = sql.String()
 	return
 }
 
+// limitPredicate returns a predicate restricting an UPDATE or DELETE statement
+// on table to the rows selected by sb, which has its columns and FROM clause
+// set to the keys and table.
+func limitPredicate(table string, keys []string, sb *selectBuilder) StatementBuilder {
+	if len(keys) == 0 {
+		keys = []string{"ctid"}
+	}
+
+	sb.columns = nil
+	for _, key := range keys {
+		sb.columns = append(sb.columns, newPart(key))
+	}
+	sb.from = newPart(table)
+
+	if len(keys) == 1 {
+		return Expr(keys[0]+" IN (?)", sb)
+	}
+	return Expr("("+strings.Join(keys, ", ")+") IN (?)", sb)
+}
+
+// limitSelect returns the subquery for emulating ORDER BY, LIMIT and OFFSET.
+func limitSelect(whereParts, orderBys []StatementBuilder, limit uint64, limitValid bool, offset uint64, offsetValid bool) *selectBuilder {
+	sb := &selectBuilder{whereParts: whereParts, orderBys: orderBys}
+	if limitValid {
+		sb.limit = rowCount(limit)
+	}
+	if offsetValid {
+		sb.offset = rowCount(offset)
+	}
+	return sb
+}
+
 func (b *updateBuilder) Prefix(sql string, args ...interface{}) UpdateBuilder {
+	b = b.clone()
 	b.prefixes = append(b.prefixes, expr{sql: sql, args: args})
 	return b
 }
 
 func (b *updateBuilder) Table(table string) UpdateBuilder {
+	b = b.clone()
 	b.table = table
 	return b
 }
 
 func (b *updateBuilder) Set(column string, value interface{}) UpdateBuilder {
+	b = b.clone()
 	b.setClauses = append(b.setClauses, setClause{column: column, value: value})
 	return b
 }
 
+func (b *updateBuilder) SetColumns(columns []string, value StatementBuilder) UpdateBuilder {
+	var v StatementBuilder
+	switch {
+	case len(columns) == 0:
+		v = expr{err: fmt.Errorf("multiple-column set clauses must have at least one column")}
+	case value == nil:
+		v = expr{err: fmt.Errorf("multiple-column set clauses must have a value")}
+	case len(columns) == 1:
+		v = rowValue{value}
+	default:
+		v = Expr("(?)", value)
+	}
+	return b.Set("("+strings.Join(columns, ", ")+")", v)
+}
+
+// rowValue is the value of a single-column SET clause, which is wrapped in
+// ROW(...) unless it is a subquery.
+type rowValue struct {
+	value StatementBuilder
+}
+
+func (r rowValue) ToSQL() (string, []interface{}, error) {
+	sql, args, err := r.value.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch r.value.(type) {
+	case SelectBuilder, ValuesBuilder:
+		return "(" + sql + ")", args, nil
+	}
+
+	trimmed := strings.TrimSpace(sql)
+	for _, keyword := range []string{"SELECT", "VALUES", "WITH", "TABLE"} {
+		if k := topLevelKeyword(trimmed, keyword); len(k) > 0 && k[0] == 0 {
+			return "(" + sql + ")", args, nil
+		}
+	}
+	return "ROW(" + sql + ")", args, nil
+}
+
+func (b *updateBuilder) SetDefault(column string) UpdateBuilder {
+	return b.Set(column, Expr("DEFAULT"))
+}
+
+func (b *updateBuilder) SetJSON(column string, path []string, value interface{}) UpdateBuilder {
+	return b.Set(column, jsonSet{column: column, path: path, value: value})
+}
+
 func (b *updateBuilder) SetMap(clauses map[string]interface{}) UpdateBuilder {
+	b = b.clone()
 	keys := make([]string, len(clauses))
 	i := 0
 	for key := range clauses {
@@ -178,40 +347,71 @@ func (b *updateBuilder) SetMap(clauses map[string]interface{}) UpdateBuilder {
 	}
 	sort.Strings(keys)
 	for _, key := range keys {
-		val := clauses[key]
-		b.Set(key, val)
+		b.setClauses = append(b.setClauses, setClause{column: key, value: clauses[key]})
 	}
 	return b
 }
 
 func (b *updateBuilder) From(from string) UpdateBuilder {
-	b.from = append(b.from, from)
+	b = b.clone()
+	b.from = append(b.from, newPart(from))
+	return b
+}
+
+func (b *updateBuilder) FromClause(pred interface{}, args ...interface{}) UpdateBuilder {
+	b = b.clone()
+	b.from = append(b.from, newPart(pred, args...))
 	return b
 }
 
 func (b *updateBuilder) Where(pred interface{}, args ...interface{}) UpdateBuilder {
+	b = b.clone()
 	b.whereParts = append(b.whereParts, newWherePart(pred, args...))
 	return b
 }
 
+func (b *updateBuilder) WhereCurrentOf(cursor string) UpdateBuilder {
+	b = b.clone()
+	b.cursor = cursor
+	return b
+}
+
 func (b *updateBuilder) OrderBy(orderBys ...string) UpdateBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	b = b.clone()
+	for _, str := range orderBys {
+		b.orderBys = append(b.orderBys, newPart(str))
+	}
+	return b
+}
+
+func (b *updateBuilder) OrderByClause(pred interface{}, args ...interface{}) UpdateBuilder {
+	b = b.clone()
+	b.orderBys = append(b.orderBys, newPart(pred, args...))
 	return b
 }
 
 func (b *updateBuilder) Limit(limit uint64) UpdateBuilder {
+	b = b.clone()
 	b.limit = limit
 	b.limitValid = true
 	return b
 }
 
 func (b *updateBuilder) Offset(offset uint64) UpdateBuilder {
+	b = b.clone()
 	b.offset = offset
 	b.offsetValid = true
 	return b
 }
 
+func (b *updateBuilder) LimitKey(columns ...string) UpdateBuilder {
+	b = b.clone()
+	b.limitKeys = columns
+	return b
+}
+
 func (b *updateBuilder) Suffix(sql string, args ...interface{}) UpdateBuilder {
+	b = b.clone()
 	b.suffixes = append(b.suffixes, expr{sql: sql, args: args})
 
 	return b
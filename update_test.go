@@ -1,6 +1,7 @@
 package sq
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,9 +16,6 @@ func TestUpdateBuilderToSQL(t *testing.T) {
 		From("f1").
 		From("f2").
 		Where("d = ?", 3).
-		OrderBy("e").
-		Limit(4).
-		Offset(5).
 		Suffix("RETURNING ?", 6)
 
 	sql, args, err := b.ToSQL()
@@ -28,7 +26,6 @@ func TestUpdateBuilderToSQL(t *testing.T) {
 			"UPDATE a SET b = ? + 1, c = ? " +
 			"FROM f1, f2 " +
 			"WHERE d = ? " +
-			"ORDER BY e LIMIT 4 OFFSET 5 " +
 			"RETURNING ?"
 	assert.Equal(t, expectedSQL, sql)
 
@@ -45,7 +42,7 @@ func TestUpdateBuilderZeroOffsetLimit(t *testing.T) {
 	sql, args, err := qb.ToSQL()
 	assert.NoError(t, err)
 
-	expectedSQL := "UPDATE a SET b = ? LIMIT 0 OFFSET 0"
+	expectedSQL := "UPDATE a SET b = ? WHERE ctid IN (SELECT ctid FROM a LIMIT 0 OFFSET 0)"
 	assert.Equal(t, expectedSQL, sql)
 
 	expectedArgs := []interface{}{true}
@@ -59,3 +56,97 @@ func TestUpdateBuilderToSQLErr(t *testing.T) {
 	_, _, err = Update("x").ToSQL()
 	assert.Error(t, err)
 }
+
+func TestUpdateBuilderConcurrent(t *testing.T) {
+	base := Update("a").Set("b", 1).Where("c = ?", 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.SetMap(Eq{"d": i}).Where("e = ?", 3).ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, "UPDATE a SET b = ?, d = ? WHERE c = ? AND e = ?", sql)
+			assert.Equal(t, []interface{}{1, i, 2, 3}, args)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestUpdateBuilderPrefixErr(t *testing.T) {
+	_, _, err := Update("a").Set("b", 1).Prefix("WITH x AS (?)", Select()).ToSQL()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderSetColumns(t *testing.T) {
+	qb := Update("t").
+		SetColumns([]string{"a", "b"}, Expr("?, ?", 1, 2)).
+		SetColumns([]string{"c", "d"}, Select("x", "y").From("u").Where("u.id = t.u_id AND u.z = ?", 3)).
+		SetDefault("e").
+		Where("id = ?", 4)
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "UPDATE t SET (a, b) = (?, ?), " +
+		"(c, d) = (SELECT x, y FROM u WHERE u.id = t.u_id AND u.z = ?), " +
+		"e = DEFAULT " +
+		"WHERE id = ?"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, args, err = Update("t").
+		SetColumns([]string{"a"}, Expr("?", 1)).
+		SetColumns([]string{"b"}, Select("x").From("u").Where("u.id = t.u_id")).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET (a) = ROW(?), (b) = (SELECT x FROM u WHERE u.id = t.u_id)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Update("t").
+		SetColumns([]string{"a"}, Values([]interface{}{1})).
+		SetColumns([]string{"b"}, Expr("select x FROM u WHERE u.id = ?", 2)).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET (a) = (VALUES (?)), (b) = (select x FROM u WHERE u.id = ?)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	_, _, err = Update("t").SetColumns(nil, Expr("1")).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Update("t").SetColumns([]string{"a"}, nil).ToSQL()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderWhereCurrentOf(t *testing.T) {
+	sql, args, err := Update("t").Set("a", 1).WhereCurrentOf("c").Suffix("RETURNING a").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? WHERE CURRENT OF c RETURNING a", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = Update("t").Set("a", 1).WhereCurrentOf("c").Where("b = ?", 2).ToSQL()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderLimit(t *testing.T) {
+	qb := Update("a").
+		Set("b", 1).
+		Where("c = ?", 2).
+		OrderBy("d").
+		Limit(3).
+		Offset(4).
+		Suffix("RETURNING e")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = ? WHERE ctid IN (SELECT ctid FROM a WHERE c = ? ORDER BY d LIMIT 3 OFFSET 4) RETURNING e", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = qb.LimitKey("id", "region").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = ? WHERE (id, region) IN (SELECT id, region FROM a WHERE c = ? ORDER BY d LIMIT 3 OFFSET 4) RETURNING e", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	_, _, err = qb.From("f").ToSQL()
+	assert.Error(t, err)
+}
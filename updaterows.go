@@ -0,0 +1,328 @@
+package sq
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/dbscan"
+)
+
+// UpdateRowsBuilder builds UPDATE statements which set the columns of many
+// rows at once from a VALUES list.
+//
+// Like SelectBuilder, UpdateRowsBuilder is immutable.
+type UpdateRowsBuilder interface {
+	// Types sets explicit SQL types for columns of the VALUES list, overriding
+	// those inferred from the Go values of the rows.
+	//
+	//   Types(map[string]string{"id": "uuid"})
+	Types(types map[string]string) UpdateRowsBuilder
+
+	// Update returns an UpdateBuilder for all the rows, which can be further
+	// extended with Where or Suffix.
+	Update() UpdateBuilder
+
+	// Chunks returns UpdateBuilders for at most size rows each, for inputs too
+	// large for a single statement.
+	Chunks(size int) []UpdateBuilder
+
+	ToSQL() (sqlStr string, args []interface{}, err error)
+}
+
+type updateRowsBuilder struct {
+	table   string
+	key     string
+	columns []string
+	rows    [][]interface{}
+	types   map[string]string
+	err     error
+}
+
+// UpdateFromRows returns a new UpdateRowsBuilder updating table from rows,
+// matching on the key column. Rows must be a slice of structs, struct
+// pointers or map[string]interface{}. Struct fields are mapped to columns
+// like they are when scanning, using the db tag or the snake cased field
+// name. Table may have an alias, which qualifies the key column.
+//
+//     UpdateFromRows("users", "id", users) ==
+//         "UPDATE users SET name = v.name FROM (VALUES (?::bigint,?::text),(?,?)) AS v(id, name) WHERE users.id = v.id"
+func UpdateFromRows(table, key string, rows interface{}) UpdateRowsBuilder {
+	b := &updateRowsBuilder{table: table, key: key}
+	b.columns, b.rows, b.err = rowsValues(rows)
+	return b
+}
+
+func (b *updateRowsBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
+	return b.Update().ToSQL()
+}
+
+func (b *updateRowsBuilder) Types(types map[string]string) UpdateRowsBuilder {
+	nb := *b
+	nb.types = types
+	return &nb
+}
+
+func (b *updateRowsBuilder) Update() UpdateBuilder {
+	types, err := b.columnTypes()
+	return b.update(b.rows, types, err)
+}
+
+func (b *updateRowsBuilder) Chunks(size int) []UpdateBuilder {
+	if size <= 0 || len(b.rows) <= size {
+		return []UpdateBuilder{b.Update()}
+	}
+
+	// Types are inferred from all the rows so every chunk gets the same casts.
+	types, err := b.columnTypes()
+
+	var chunks []UpdateBuilder
+	for i := 0; i < len(b.rows); i += size {
+		end := i + size
+		if end > len(b.rows) {
+			end = len(b.rows)
+		}
+		chunks = append(chunks, b.update(b.rows[i:end:end], types, err))
+	}
+	return chunks
+}
+
+// columnTypes returns the SQL type of each column, from Types or inferred
+// from the values of all the rows. Columns of expressions have no type.
+func (b *updateRowsBuilder) columnTypes() ([]string, error) {
+	types := make([]string, len(b.columns))
+	for i, column := range b.columns {
+		if t, ok := b.types[column]; ok {
+			types[i] = t
+			continue
+		}
+		t, ok := inferColumnType(b.rows, i)
+		if !ok && column != b.key {
+			return nil, fmt.Errorf("update from rows can't infer the type of column %s, set it with Types", column)
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+func (b *updateRowsBuilder) update(rows [][]interface{}, types []string, typesErr error) UpdateBuilder {
+	ub := &updateBuilder{table: b.table}
+
+	err := b.err
+	if err == nil && len(b.key) == 0 {
+		err = fmt.Errorf("update from rows must specify a key column")
+	}
+	if err == nil && len(rows) == 0 {
+		err = fmt.Errorf("update from rows must have at least one row")
+	}
+
+	keyIndex := -1
+	for i, column := range b.columns {
+		if column == b.key {
+			keyIndex = i
+		}
+	}
+	if err == nil && keyIndex == -1 {
+		err = fmt.Errorf("update from rows must have a value for key column %s", b.key)
+	}
+	if err == nil && len(b.columns) < 2 {
+		err = fmt.Errorf("update from rows must have at least one column besides the key")
+	}
+	if err == nil {
+		err = typesErr
+	}
+
+	if err != nil {
+		ub.setClauses = []setClause{{column: b.key, value: expr{err: err}}}
+		return ub
+	}
+
+	for _, column := range b.columns {
+		if column != b.key {
+			ub.setClauses = append(ub.setClauses, setClause{column: column, value: Expr("v." + column)})
+		}
+	}
+
+	ub.from = []StatementBuilder{&valuesBuilder{
+		rows:    rows,
+		types:   types,
+		alias:   "v",
+		columns: b.columns,
+	}}
+	// An aliased table such as "users u" is qualified by its alias.
+	qualifier := b.table
+	if fields := strings.Fields(b.table); len(fields) > 1 {
+		qualifier = fields[len(fields)-1]
+	}
+	ub.whereParts = []StatementBuilder{newWherePart(fmt.Sprintf("%s.%s = v.%s", qualifier, b.key, b.key))}
+
+	return ub
+}
+
+// rowsValues returns the columns and values of a slice of structs, struct
+// pointers or maps.
+func rowsValues(rows interface{}) (columns []string, values [][]interface{}, err error) {
+	rowsVal := reflect.ValueOf(rows)
+	if rowsVal.Kind() != reflect.Slice && rowsVal.Kind() != reflect.Array {
+		err = fmt.Errorf("expected slice of rows, not %T", rows)
+		return
+	}
+
+	for i := 0; i < rowsVal.Len(); i++ {
+		rowVal := reflect.Indirect(rowsVal.Index(i))
+		for rowVal.Kind() == reflect.Interface || rowVal.Kind() == reflect.Ptr {
+			rowVal = reflect.Indirect(rowVal.Elem())
+		}
+
+		var rowColumns []string
+		var row []interface{}
+
+		switch rowVal.Kind() {
+		case reflect.Map:
+			m, ok := rowVal.Interface().(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("expected map[string]interface{} row, not %s", rowVal.Type())
+				return
+			}
+			for column := range m {
+				rowColumns = append(rowColumns, column)
+			}
+			sort.Strings(rowColumns)
+			for _, column := range rowColumns {
+				row = append(row, m[column])
+			}
+		case reflect.Struct:
+			rowColumns, row = structValues(rowVal, rowColumns, row)
+		default:
+			err = fmt.Errorf("expected struct or map row, not %s", rowVal.Kind())
+			return
+		}
+
+		if i == 0 {
+			columns = rowColumns
+		} else if strings.Join(rowColumns, ",") != strings.Join(columns, ",") {
+			err = fmt.Errorf("rows must all have the same columns")
+			return
+		}
+		values = append(values, row)
+	}
+
+	return
+}
+
+// structValues appends the columns and values of the exported fields of a
+// struct, flattening embedded structs.
+func structValues(v reflect.Value, columns []string, values []interface{}) ([]string, []interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag, hasTag := field.Tag.Lookup("db")
+		if idx := strings.Index(tag, ","); idx != -1 {
+			tag = tag[:idx]
+		}
+		if tag == "-" {
+			continue
+		}
+
+		fieldVal := v.Field(i)
+		if field.Anonymous && !hasTag {
+			fieldVal = reflect.Indirect(fieldVal)
+			if fieldVal.Kind() == reflect.Struct {
+				columns, values = structValues(fieldVal, columns, values)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		column := tag
+		if column == "" {
+			column = dbscan.SnakeCaseMapper(field.Name)
+		}
+		columns = append(columns, column)
+		values = append(values, fieldVal.Interface())
+	}
+	return columns, values
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+	nullTypes = map[reflect.Type]string{
+		reflect.TypeOf(sql.NullBool{}):    "boolean",
+		reflect.TypeOf(sql.NullInt32{}):   "integer",
+		reflect.TypeOf(sql.NullInt64{}):   "bigint",
+		reflect.TypeOf(sql.NullFloat64{}): "double precision",
+		reflect.TypeOf(sql.NullString{}):  "text",
+		reflect.TypeOf(sql.NullTime{}):    "timestamptz",
+	}
+)
+
+// inferColumnType returns the PostgreSQL type for column i based on the first
+// non-nil value. It returns false if the type can't be inferred, unless the
+// values are expressions which don't need a cast.
+func inferColumnType(rows [][]interface{}, i int) (string, bool) {
+	for _, row := range rows {
+		if row[i] == nil {
+			continue
+		}
+		if _, ok := row[i].(StatementBuilder); ok {
+			return "", true
+		}
+
+		t := reflect.TypeOf(row[i])
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		typ := goTypeToSQL(t)
+		return typ, typ != ""
+	}
+	return "", false
+}
+
+func goTypeToSQL(t reflect.Type) string {
+	if typ, ok := nullTypes[t]; ok {
+		return typ
+	}
+	if t == timeType {
+		return "timestamptz"
+	}
+	if t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType) {
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "smallint"
+	case reflect.Int32, reflect.Uint16:
+		return "integer"
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "bigint"
+	case reflect.Float32:
+		return "real"
+	case reflect.Float64:
+		return "double precision"
+	case reflect.String:
+		return "text"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytea"
+		}
+		if elem := goTypeToSQL(t.Elem()); elem != "" {
+			return elem + "[]"
+		}
+	}
+	return ""
+}
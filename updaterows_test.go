@@ -0,0 +1,152 @@
+package sq
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type updateRowsBase struct {
+	UpdateTime time.Time
+}
+
+type updateRowsUser struct {
+	updateRowsBase
+	ID       int64
+	Name     string `db:"full_name"`
+	Nickname sql.NullString
+	Tags     []string
+	Ignored  string `db:"-"`
+	internal string
+}
+
+func TestUpdateFromRowsStructs(t *testing.T) {
+	now := time.Now()
+	users := []*updateRowsUser{
+		{updateRowsBase{now}, 1, "Jane", sql.NullString{}, []string{"a"}, "x", "y"},
+		{updateRowsBase{now}, 2, "Mike", sql.NullString{String: "m", Valid: true}, nil, "x", "y"},
+	}
+
+	qb := UpdateFromRows("users", "id", users)
+	query, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "UPDATE users SET update_time = v.update_time, full_name = v.full_name, nickname = v.nickname, tags = v.tags " +
+		"FROM (VALUES (?::timestamptz,?::bigint,?::text,?::text,?::text[]),(?,?,?,?,?)) AS v(update_time, id, full_name, nickname, tags) " +
+		"WHERE users.id = v.id"
+	assert.Equal(t, expectedSQL, query)
+
+	expectedArgs := []interface{}{
+		now, int64(1), "Jane", sql.NullString{}, []string{"a"},
+		now, int64(2), "Mike", sql.NullString{String: "m", Valid: true}, []string(nil),
+	}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestUpdateFromRowsMaps(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": "a", "name": nil, "score": 1.5},
+		{"id": "b", "name": "Mike", "score": 2.5},
+	}
+
+	qb := UpdateFromRows("users", "id", rows).
+		Types(map[string]string{"id": "uuid"}).
+		Update().
+		Where("users.active").
+		Suffix("RETURNING users.id")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "UPDATE users SET name = v.name, score = v.score " +
+		"FROM (VALUES (?::uuid,?::text,?::double precision),(?,?,?)) AS v(id, name, score) " +
+		"WHERE users.id = v.id AND users.active RETURNING users.id"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{"a", nil, 1.5, "b", "Mike", 2.5}, args)
+}
+
+func TestUpdateFromRowsAlias(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "name": "a"}}
+
+	for _, table := range []string{"users u", "public.users AS u"} {
+		sql, _, err := UpdateFromRows(table, "id", rows).ToSQL()
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE "+table+" SET name = v.name FROM (VALUES (?::bigint,?::text)) AS v(id, name) WHERE u.id = v.id", sql)
+	}
+}
+
+func TestUpdateFromRowsChunks(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+		{"id": 3, "name": "c"},
+	}
+
+	chunks := UpdateFromRows("users", "id", rows).Chunks(2)
+	assert.Len(t, chunks, 2)
+
+	sql, args, err := chunks[0].ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = v.name FROM (VALUES (?::bigint,?::text),(?,?)) AS v(id, name) WHERE users.id = v.id", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b"}, args)
+
+	sql, args, err = chunks[1].ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = v.name FROM (VALUES (?::bigint,?::text)) AS v(id, name) WHERE users.id = v.id", sql)
+	assert.Equal(t, []interface{}{3, "c"}, args)
+
+	assert.Len(t, UpdateFromRows("users", "id", rows).Chunks(0), 1)
+}
+
+func TestUpdateFromRowsErr(t *testing.T) {
+	_, _, err := UpdateFromRows("users", "id", 1).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = UpdateFromRows("users", "id", []map[string]interface{}{}).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = UpdateFromRows("users", "id", []map[string]interface{}{{"name": "a"}}).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = UpdateFromRows("users", "id", []map[string]interface{}{{"id": 1}}).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = UpdateFromRows("users", "id", []map[string]interface{}{{"id": 1, "a": 1}, {"id": 2, "b": 2}}).ToSQL()
+	assert.Error(t, err)
+}
+
+func TestUpdateFromRowsUntyped(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "score": nil},
+		{"id": 2, "score": 3},
+		{"id": 3, "score": nil},
+	}
+
+	// The type is inferred from all the rows, not just those of each chunk.
+	chunks := UpdateFromRows("users", "id", rows).Chunks(1)
+	assert.Len(t, chunks, 3)
+	for _, chunk := range chunks {
+		sql, _, err := chunk.ToSQL()
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE users SET score = v.score FROM (VALUES (?::bigint,?::bigint)) AS v(id, score) WHERE users.id = v.id", sql)
+	}
+
+	rows = []map[string]interface{}{{"id": 1, "score": nil}}
+
+	_, _, err := UpdateFromRows("users", "id", rows).ToSQL()
+	assert.EqualError(t, err, "update from rows can't infer the type of column score, set it with Types")
+
+	sql, _, err := UpdateFromRows("users", "id", rows).Types(map[string]string{"score": "integer"}).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET score = v.score FROM (VALUES (?::bigint,?::integer)) AS v(id, score) WHERE users.id = v.id", sql)
+
+	sql, _, err = UpdateFromRows("users", "id", []map[string]interface{}{{"id": 1, "updated_at": Expr("now()")}}).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET updated_at = v.updated_at FROM (VALUES (?::bigint,now())) AS v(id, updated_at) WHERE users.id = v.id", sql)
+}
+
+func TestUpdateFromRowsErrMessage(t *testing.T) {
+	_, _, err := UpdateFromRows("users", "id", []map[string]interface{}{{"id": 1}}).ToSQL()
+	assert.EqualError(t, err, "update from rows must have at least one column besides the key")
+}
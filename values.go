@@ -0,0 +1,161 @@
+package sq
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ValuesBuilder builds SQL VALUES lists, which can be used as table
+// expressions in FROM and JOIN clauses, common table expressions and IN
+// predicates.
+//
+// Like SelectBuilder, ValuesBuilder is immutable.
+type ValuesBuilder interface {
+	// Values adds a single row's values to the list.
+	Values(values ...interface{}) ValuesBuilder
+
+	// Types sets explicit types for the columns, which are added as casts to the
+	// values of the first row so PostgreSQL can infer the column types. Columns
+	// with an empty type are not cast.
+	//
+	//   Types("int", "text") == "VALUES (?::int,?::text),(?,?)"
+	Types(types ...string) ValuesBuilder
+
+	// As sets the table alias and optionally the column names, wrapping the
+	// list in parentheses for use as a table expression.
+	//
+	//   As("v", "id", "name") == "(VALUES ...) AS v(id, name)"
+	As(alias string, columns ...string) ValuesBuilder
+
+	ToSQL() (sqlStr string, args []interface{}, err error)
+}
+
+type valuesBuilder struct {
+	rows    [][]interface{}
+	types   []string
+	alias   string
+	columns []string
+}
+
+// Values returns a new ValuesBuilder with the given rows.
+//
+//     .FromClause(Values([]interface{}{1, "a"}, []interface{}{2, "b"}).As("v", "id", "name"))
+func Values(rows ...[]interface{}) ValuesBuilder {
+	return &valuesBuilder{rows: rows[:len(rows):len(rows)]}
+}
+
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *valuesBuilder) clone() *valuesBuilder {
+	nb := *b
+	nb.rows = nb.rows[:len(nb.rows):len(nb.rows)]
+	return &nb
+}
+
+func (b *valuesBuilder) ToSQL() (sqlStr string, args []interface{}, err error) {
+	if len(b.rows) == 0 {
+		err = fmt.Errorf("values lists must have at least one row")
+		return
+	}
+
+	width := len(b.rows[0])
+	if width == 0 {
+		err = fmt.Errorf("values lists must have at least one value in each row")
+		return
+	}
+	for _, row := range b.rows {
+		if len(row) != width {
+			err = fmt.Errorf("values lists must have the same number of values in each row")
+			return
+		}
+	}
+	if len(b.types) > 0 && len(b.types) != width {
+		err = fmt.Errorf("values lists must have a type for each column")
+		return
+	}
+	if len(b.columns) > 0 && len(b.columns) != width {
+		err = fmt.Errorf("values lists must have a name for each column")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	if len(b.alias) > 0 {
+		sql.WriteString("(")
+	}
+
+	sql.WriteString("VALUES ")
+
+	args, err = appendValuesToSQL(sql, b.rows, b.types, args)
+	if err != nil {
+		return
+	}
+
+	if len(b.alias) > 0 {
+		sql.WriteString(") AS ")
+		sql.WriteString(b.alias)
+
+		if len(b.columns) > 0 {
+			sql.WriteString("(")
+			sql.WriteString(strings.Join(b.columns, ", "))
+			sql.WriteString(")")
+		}
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// appendValuesToSQL writes the rows of a VALUES list, casting the values of the
+// first row to types if given.
+func appendValuesToSQL(sql *bytes.Buffer, rows [][]interface{}, types []string, args []interface{}) ([]interface{}, error) {
+	valuesStrings := make([]string, len(rows))
+	for r, row := range rows {
+
+		valueStrings := make([]string, len(row))
+
+		for v, val := range row {
+			switch typedVal := val.(type) {
+			case StatementBuilder:
+				valSQL, valArgs, err := typedVal.ToSQL()
+				if err != nil {
+					return nil, err
+				}
+
+				valueStrings[v] = valSQL
+				args = append(args, valArgs...)
+			default:
+				valueStrings[v] = "?"
+				args = append(args, val)
+			}
+
+			if r == 0 && len(types) > 0 && types[v] != "" {
+				valueStrings[v] = fmt.Sprintf("%s::%s", valueStrings[v], types[v])
+			}
+		}
+
+		valuesStrings[r] = fmt.Sprintf("(%s)", strings.Join(valueStrings, ","))
+	}
+	sql.WriteString(strings.Join(valuesStrings, ","))
+
+	return args, nil
+}
+
+func (b *valuesBuilder) Values(values ...interface{}) ValuesBuilder {
+	b = b.clone()
+	b.rows = append(b.rows, values)
+	return b
+}
+
+func (b *valuesBuilder) Types(types ...string) ValuesBuilder {
+	b = b.clone()
+	b.types = types
+	return b
+}
+
+func (b *valuesBuilder) As(alias string, columns ...string) ValuesBuilder {
+	b = b.clone()
+	b.alias = alias
+	b.columns = columns
+	return b
+}
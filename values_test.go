@@ -0,0 +1,80 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesToSQL(t *testing.T) {
+	sql, args, err := Values([]interface{}{1, "a"}).Values(2, Expr("upper(?)", "b")).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES (?,?),(?,upper(?))", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b"}, args)
+
+	sql, args, err = Values([]interface{}{1, "a"}, []interface{}{2, "b"}).
+		Types("int", "text").
+		As("v", "id", "name").
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "(VALUES (?::int,?::text),(?,?)) AS v(id, name)", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b"}, args)
+}
+
+func TestValuesInSelect(t *testing.T) {
+	v := Values([]interface{}{1, "a"}, []interface{}{2, "b"}).As("v", "id", "name")
+
+	qb := Select("t.id", "v.name").
+		FromClause(v).
+		Join("? ON t.id = v.id AND t.x = ?", Values([]interface{}{3}).As("w", "id"), 4).
+		Where(Eq{"t.id": Values([]interface{}{5}, []interface{}{6})})
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+
+	expectedSQL := "SELECT t.id, v.name " +
+		"FROM (VALUES (?,?),(?,?)) AS v(id, name) " +
+		"JOIN (VALUES (?)) AS w(id) ON t.id = v.id AND t.x = ? " +
+		"WHERE t.id IN (VALUES (?),(?))"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b", 3, 4, 5, 6}, args)
+
+	sql, args, err = With("v", "id").
+		As(Values([]interface{}{1}, []interface{}{2})).
+		Select("id").
+		FromClause("v WHERE id > ?", 1).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH v(id) AS (VALUES (?),(?)) SELECT id FROM v WHERE id > ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 1}, args)
+}
+
+func TestValuesInUpdate(t *testing.T) {
+	qb := Update("t").
+		Set("name", Expr("v.name")).
+		FromClause(Values([]interface{}{1, "a"}).Types("int", "text").As("v", "id", "name")).
+		Where("t.id = v.id")
+	sql, args, err := qb.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET name = v.name FROM (VALUES (?::int,?::text)) AS v(id, name) WHERE t.id = v.id", sql)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+}
+
+func TestValuesToSQLErr(t *testing.T) {
+	_, _, err := Values().ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Values([]interface{}{1}, []interface{}{1, 2}).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Values([]interface{}{}).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Values([]interface{}{}, []interface{}{}).ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Values([]interface{}{1}).Types("int", "text").ToSQL()
+	assert.Error(t, err)
+
+	_, _, err = Values([]interface{}{1}).As("v", "a", "b").ToSQL()
+	assert.Error(t, err)
+}
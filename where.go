@@ -3,6 +3,8 @@ package sq
 import "fmt"
 
 // WhereBuilder builds SQL where statements.
+//
+// Like SelectBuilder, WhereBuilder is immutable.
 type WhereBuilder interface {
 	// Where adds WHERE expressions to the query.
 	//
@@ -28,7 +30,15 @@ func NewWhereBuilder() WhereBuilder {
 	return &whereBuilder{}
 }
 
+// clone returns a copy of b, see selectBuilder.clone.
+func (b *whereBuilder) clone() *whereBuilder {
+	nb := *b
+	nb.whereParts = nb.whereParts[:len(nb.whereParts):len(nb.whereParts)]
+	return &nb
+}
+
 func (b *whereBuilder) Where(pred interface{}, args ...interface{}) WhereBuilder {
+	b = b.clone()
 	b.whereParts = append(b.whereParts, newWherePart(pred, args...))
 	return b
 }
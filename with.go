@@ -6,6 +6,8 @@ import (
 )
 
 // WithBuilder builds SQL WITH statements.
+//
+// Like SelectBuilder, WithBuilder is immutable.
 type WithBuilder interface {
 	// With adds a new common table expression to the query.
 	With(name string, field ...string) WithBuilder
@@ -39,7 +41,16 @@ type withBuilder struct {
 	err       error
 }
 
+// clone returns a copy of w. Unlike the other builders the parts are copied
+// rather than shared, as As modifies the current part.
+func (w *withBuilder) clone() *withBuilder {
+	nw := *w
+	nw.withParts = append([]*withPart(nil), w.withParts...)
+	return &nw
+}
+
 func (w *withBuilder) With(name string, field ...string) WithBuilder {
+	w = w.clone()
 	w.withParts = append(w.withParts, &withPart{
 		name:   name,
 		fields: field,
@@ -47,27 +58,32 @@ func (w *withBuilder) With(name string, field ...string) WithBuilder {
 	return w
 }
 
+// current returns a copy of the last common table expression, replacing it in
+// w so that it can be modified. It must only be called on a clone.
 func (w *withBuilder) current() *withPart {
 	if len(w.withParts) == 0 {
 		w.err = errors.New("with statements must have WITH")
 		return &withPart{}
 	}
-	return w.withParts[len(w.withParts)-1]
+	p := *w.withParts[len(w.withParts)-1]
+	w.withParts[len(w.withParts)-1] = &p
+	return &p
 }
 
 func (w *withBuilder) Recursive() WithBuilder {
+	w = w.clone()
 	w.recursive = true
 	return w
 }
 
 func (w *withBuilder) As(b StatementBuilder) WithBuilder {
+	w = w.clone()
 	w.current().as = b
 	return w
 }
 
 func (w *withBuilder) Select(columns ...string) SelectBuilder {
-	b := &selectBuilder{}
-	b.Columns(columns...)
+	b := NewSelectBuilder().Columns(columns...).(*selectBuilder)
 
 	if w.err != nil {
 		b.prefixes = []expr{{err: w.err}}
@@ -1,6 +1,7 @@
 package sq
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -113,3 +114,28 @@ func TestWithError(t *testing.T) {
 		ToSQL()
 	require.EqualError(t, err, "with statements must have AS statement")
 }
+
+func TestWithBuilderConcurrent(t *testing.T) {
+	base := With("one").As(Select("a").From("b"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.With("two").As(Select("c").From("d").Where("e = ?", i)).
+				Select("a").
+				From("one").
+				ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, "WITH one AS (SELECT a FROM b), two AS (SELECT c FROM d WHERE e = ?) SELECT a FROM one", sql)
+			assert.Equal(t, []interface{}{i}, args)
+		}(i)
+	}
+	wg.Wait()
+
+	sql, _, err := base.Select("a").From("one").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH one AS (SELECT a FROM b) SELECT a FROM one", sql)
+}